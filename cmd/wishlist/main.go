@@ -1,21 +1,113 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"log"
-
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/WellyngtonF/WishListCLI/internal/alert"
+	"github.com/WellyngtonF/WishListCLI/internal/cli"
+	"github.com/WellyngtonF/WishListCLI/internal/config"
+	"github.com/WellyngtonF/WishListCLI/internal/logging"
 	"github.com/WellyngtonF/WishListCLI/internal/menu"
+	"github.com/WellyngtonF/WishListCLI/internal/persistence"
+	"github.com/WellyngtonF/WishListCLI/internal/repository"
+	"github.com/WellyngtonF/WishListCLI/internal/scraper/sources"
 	"github.com/awesome-gocui/gocui"
 )
 
 const (
-	menuViewName = "menu"
-	mainViewName = "main"
+	menuViewName        = "menu"
+	mainViewName        = "main"
+	logViewName         = "log"
+	logViewHeight       = 8
+	logRingSize         = 200
+	defaultWishlistFile = "wishlist.csv"
 )
 
-var currentSelection = 0
+var (
+	currentSelection = 0
+	repo             *repository.Repository
+	// logRing keeps the recent log lines shown in the TUI log pane
+	logRing *logging.Ring
+)
 
 func main() {
+	wishlistFile := flag.String("file", defaultWishlistFile, "wishlist file")
+	flag.Usage = func() {
+		cli.PrintUsage(os.Stderr)
+	}
+	flag.Parse()
+
+	if err := config.Load(); err != nil {
+		fmt.Fprintf(os.Stderr, "error reading config file: %v\n", err)
+	}
+
+	// the CLI logs to stderr, the TUI to its log pane
+	var console io.Writer = os.Stderr
+	if flag.NArg() == 0 {
+		logRing = logging.NewRing(logRingSize)
+		console = logRing
+	}
+
+	logger, logCloser := newLogger(console)
+
+	store, err := persistence.Open(*wishlistFile, persistence.BackupConfigFromViper())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(cli.ExitError)
+	}
+	repo = repository.New(store).WithLogger(logger)
+	alert.SetBaseDir(filepath.Dir(*wishlistFile))
+
+	// SQLite keeps the history in the same database, CSV uses its own file
+	if history, ok := store.(persistence.HistoryStore); ok {
+		repo.WithHistory(history)
+	} else {
+		repo.WithHistory(persistence.NewCSVHistoryStore(filepath.Join(filepath.Dir(*wishlistFile), persistence.CSVHistoryFile)))
+	}
+
+	code := cli.ExitOK
+	if flag.NArg() > 0 {
+		code = cli.New(repo, os.Stdout, os.Stderr).WithFile(*wishlistFile).Run(flag.Args())
+	} else {
+		runTUI()
+	}
+
+	if closer, ok := store.(io.Closer); ok {
+		closer.Close()
+	}
+	logCloser.Close()
+	os.Exit(code)
+}
+
+// newLogger creates the logger from the log section of the config and makes
+// it the default one, used by the scrapers
+func newLogger(console io.Writer) (*slog.Logger, io.Closer) {
+	cfg, err := logging.ConfigFromViper()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error reading log config: %v\n", err)
+	}
+
+	logger, closer, err := logging.New(cfg, console)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		cfg.File = ""
+		logger, closer, _ = logging.New(cfg, console)
+	}
+
+	slog.SetDefault(logger)
+	sources.SetLogger(logger)
+	return logger, closer
+}
+
+// runTUI runs the interactive interface until the user exits
+func runTUI() {
 	g, err := gocui.NewGui(gocui.OutputNormal, true)
 	if err != nil {
 		log.Panicln(err)
@@ -25,6 +117,11 @@ func main() {
 	g.Mouse = true
 	g.SetManagerFunc(layout)
 
+	logRing.SetNotify(func() {
+		g.Update(updateLogView)
+	})
+	defer logRing.SetNotify(nil)
+
 	if err := setKeybindings(g); err != nil {
 		log.Panicln(err)
 	}
@@ -46,7 +143,15 @@ func layout(g *gocui.Gui) error {
 		updateMenuView(g)
 	}
 
-	if v, err := g.SetView(mainViewName, menuWidth, 0, maxX-1, maxY-1, 0); err != nil {
+	if v, err := g.SetView(logViewName, menuWidth, maxY-logViewHeight-1, maxX-1, maxY-1, 0); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Title = "Log"
+		updateLogView(g)
+	}
+
+	if v, err := g.SetView(mainViewName, menuWidth, 0, maxX-1, maxY-logViewHeight-2, 0); err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
@@ -74,6 +179,23 @@ func updateMenuView(g *gocui.Gui) error {
 	return nil
 }
 
+// updateLogView shows the last log lines fitting in the log pane
+func updateLogView(g *gocui.Gui) error {
+	v, err := g.View(logViewName)
+	if err != nil {
+		return nil
+	}
+
+	lines := logRing.Lines()
+	if _, height := v.Size(); len(lines) > height {
+		lines = lines[len(lines)-height:]
+	}
+
+	v.Clear()
+	fmt.Fprint(v, strings.Join(lines, "\n"))
+	return nil
+}
+
 func setKeybindings(g *gocui.Gui) error {
 	if err := g.SetKeybinding("", gocui.MouseLeft, gocui.ModNone, onClick); err != nil {
 		return err
@@ -102,28 +224,23 @@ func handleMenuOption(g *gocui.Gui, choice int) error {
 		return err
 	}
 
+	menu.CloseScreen(g)
 	mainView.Clear()
 
 	switch choice {
 	case 0:
-		return menu.HandleAddItem(g, mainView)
+		return menu.HandleAddItem(g, mainView, repo)
 	case 1:
-		mainView.Title = "View Wishlist"
-		fmt.Fprintln(mainView, "View Wishlist")
-		// Implement view wishlist functionality
+		return menu.HandleViewWishlist(g, mainView, repo)
 	case 2:
-		mainView.Title = "Update Item in Wishlist"
-		fmt.Fprintln(mainView, "Update Item in Wishlist")
-		// Implement update item functionality
+		return menu.HandleUpdateItem(g, mainView, repo)
 	case 3:
-		mainView.Title = "Delete Item from Wishlist"
-		fmt.Fprintln(mainView, "Delete Item from Wishlist")
-		// Implement delete item functionality
+		return menu.HandleDeleteItem(g, mainView, repo)
 	case 4:
-		mainView.Title = "Run Web Scraping"
-		fmt.Fprintln(mainView, "Run Web Scraping")
-		// Implement web scraping functionality
+		return menu.HandleRunScraping(g, mainView, repo)
 	case 5:
+		return menu.HandleBackups(g, mainView, repo)
+	case 6:
 		return gocui.ErrQuit
 	default:
 		fmt.Fprintln(mainView, "Invalid option. Please choose again.")
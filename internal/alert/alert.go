@@ -0,0 +1,205 @@
+package alert
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	"github.com/WellyngtonF/WishListCLI/internal/scraper/sources"
+	"github.com/spf13/viper"
+)
+
+// Kind is the rule that triggered an alert
+type Kind string
+
+const (
+	// KindBelowMaxPrice is emitted when an offer costs at most the item MaxPrice
+	KindBelowMaxPrice Kind = "below_max_price"
+	// KindPriceDrop is emitted when the price dropped by Rules.DropPercent since the last scrape
+	KindPriceDrop Kind = "price_drop"
+)
+
+// Event is a deal found for an item
+type Event struct {
+	Kind          Kind
+	ItemID        string
+	ItemName      string
+	Source        string
+	Price         float64
+	URL           string
+	Seller        string
+	MaxPrice      float64
+	PreviousPrice float64
+	DropPercent   float64
+	At            time.Time
+}
+
+// Message returns a human readable description of the event
+func (e Event) Message() string {
+	switch e.Kind {
+	case KindPriceDrop:
+		return fmt.Sprintf("%s dropped %.1f%% on %s: %.2f (was %.2f) %s",
+			e.ItemName, e.DropPercent, e.Source, e.Price, e.PreviousPrice, e.URL)
+	default:
+		return fmt.Sprintf("%s is below your max price on %s: %.2f (max %.2f) %s",
+			e.ItemName, e.Source, e.Price, e.MaxPrice, e.URL)
+	}
+}
+
+// key identifies the deal of the event for de-duplication. Items are
+// identified by ID so renaming them does not report their deals again.
+func (e Event) key() string {
+	itemKey := e.ItemID
+	if itemKey == "" {
+		itemKey = e.ItemName
+	}
+	return fmt.Sprintf("%s|%s|%s|%s|%.2f", e.Kind, itemKey, e.Source, e.URL, e.Price)
+}
+
+// Rules are the alert rules applied besides the item MaxPrice
+type Rules struct {
+	// DropPercent emits a price drop alert when the price is at least this
+	// percent lower than the last one seen, zero disables the rule
+	DropPercent float64
+}
+
+// Evaluator checks scrape results against the alert rules and sends new deals to a notifier
+type Evaluator struct {
+	rules    Rules
+	notifier Notifier
+	deduper  Deduper
+}
+
+// NewEvaluator creates an evaluator sending events to the notifier. Events
+// already seen by the deduper are not sent again. Without a notifier, nil or
+// an empty MultiNotifier, events are only returned and never marked as seen.
+func NewEvaluator(rules Rules, notifier Notifier, deduper Deduper) *Evaluator {
+	if deduper == nil {
+		deduper = NewMemoryDeduper()
+	}
+	if notifiers, ok := notifier.(MultiNotifier); ok && len(notifiers) == 0 {
+		notifier = nil
+	}
+
+	return &Evaluator{
+		rules:    rules,
+		notifier: notifier,
+		deduper:  deduper,
+	}
+}
+
+// RulesFromConfig reads the rules from the alerts section of the config:
+//
+//	alerts:
+//	  drop_percent: 10
+func RulesFromConfig() Rules {
+	return Rules{DropPercent: viper.GetFloat64("alerts.drop_percent")}
+}
+
+// Evaluate returns the events triggered by the offer and sends the ones not
+// reported before, which are marked as seen once the notifier received them.
+// Without a notifier every event is returned. previous is the last price seen
+// for the item on the source and may be nil.
+func (e *Evaluator) Evaluate(itm item.Item, source string, offer sources.Offer, previous *item.PriceObservation) ([]Event, error) {
+	base := Event{
+		ItemID:   itm.ID,
+		ItemName: itm.Name,
+		Source:   source,
+		Price:    offer.Price,
+		URL:      offer.URL,
+		Seller:   offer.Seller,
+		MaxPrice: itm.MaxPrice,
+		At:       time.Now(),
+	}
+
+	var events []Event
+
+	if itm.MaxPrice > 0 && offer.Price <= itm.MaxPrice {
+		event := base
+		event.Kind = KindBelowMaxPrice
+		events = append(events, event)
+	}
+
+	if e.rules.DropPercent > 0 && previous != nil && previous.Price > 0 {
+		drop := (previous.Price - offer.Price) / previous.Price * 100
+		if drop >= e.rules.DropPercent {
+			event := base
+			event.Kind = KindPriceDrop
+			event.PreviousPrice = previous.Price
+			event.DropPercent = drop
+			events = append(events, event)
+		}
+	}
+
+	if e.notifier == nil {
+		return events, nil
+	}
+
+	var sent []Event
+	for _, event := range events {
+		seen, err := e.deduper.Seen(event.key())
+		if err != nil {
+			return sent, err
+		}
+		if seen {
+			continue
+		}
+
+		if err := e.notifier.Notify(event); err != nil {
+			return sent, fmt.Errorf("error sending alert: %v", err)
+		}
+
+		if err := e.deduper.Mark(event.key(), event.At); err != nil {
+			return sent, err
+		}
+		sent = append(sent, event)
+	}
+
+	return sent, nil
+}
+
+// Defaults of the alerts section of the config
+const (
+	defaultStateFile = "alerts_sent.json"
+	defaultRetention = 7 * 24 * time.Hour
+)
+
+// baseDir is the directory relative state files are kept in
+var baseDir string
+
+// SetBaseDir sets the directory a relative alerts.state_file is resolved
+// against, the directory of the wishlist file
+func SetBaseDir(dir string) {
+	baseDir = dir
+}
+
+// NewEvaluatorFromConfig creates an evaluator with the rules, notifiers and
+// de-duplication file of the alerts section of the config. extra notifiers
+// receive the events besides the configured ones. A relative state file is
+// kept in the directory set by SetBaseDir.
+//
+//	alerts:
+//	  state_file: alerts_sent.json
+//	  dedup_retention: 168h
+func NewEvaluatorFromConfig(extra ...Notifier) *Evaluator {
+	stateFile := viper.GetString("alerts.state_file")
+	if stateFile == "" {
+		stateFile = defaultStateFile
+	}
+	if !filepath.IsAbs(stateFile) {
+		stateFile = filepath.Join(baseDir, stateFile)
+	}
+
+	retention := defaultRetention
+	if viper.IsSet("alerts.dedup_retention") {
+		retention = viper.GetDuration("alerts.dedup_retention")
+	}
+
+	notifiers := MultiNotifier(extra)
+	if configured := NotifierFromConfig(); configured != nil {
+		notifiers = append(notifiers, configured)
+	}
+
+	return NewEvaluator(RulesFromConfig(), notifiers, NewFileDeduper(stateFile, retention))
+}
@@ -0,0 +1,179 @@
+package alert
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	"github.com/WellyngtonF/WishListCLI/internal/scraper/sources"
+	"github.com/spf13/viper"
+)
+
+// recorder is a notifier keeping the events it received
+type recorder struct {
+	events []Event
+	err    error
+}
+
+func (r *recorder) Notify(event Event) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.events = append(r.events, event)
+	return nil
+}
+
+func TestEvaluate(t *testing.T) {
+	ps5 := item.Item{ID: "ps5", Name: "PS5", MaxPrice: 3500}
+
+	tests := []struct {
+		name      string
+		rules     Rules
+		itm       item.Item
+		price     float64
+		previous  *item.PriceObservation
+		wantKinds []Kind
+		wantDrop  float64
+	}{
+		{"below max price", Rules{}, ps5, 3400, nil, []Kind{KindBelowMaxPrice}, 0},
+		{"at max price", Rules{}, ps5, 3500, nil, []Kind{KindBelowMaxPrice}, 0},
+		{"above max price", Rules{}, ps5, 3600, nil, nil, 0},
+		{"without max price", Rules{}, item.Item{ID: "x", Name: "Xbox"}, 100, nil, nil, 0},
+		{"drop", Rules{DropPercent: 10}, ps5, 3600, &item.PriceObservation{Price: 4000}, []Kind{KindPriceDrop}, 10},
+		{"small drop", Rules{DropPercent: 10}, ps5, 3700, &item.PriceObservation{Price: 4000}, nil, 0},
+		{"drop below max price", Rules{DropPercent: 5}, ps5, 3000, &item.PriceObservation{Price: 4000}, []Kind{KindBelowMaxPrice, KindPriceDrop}, 25},
+		{"price rise", Rules{DropPercent: 5}, ps5, 4400, &item.PriceObservation{Price: 4000}, nil, 0},
+		{"drop rule disabled", Rules{}, ps5, 3600, &item.PriceObservation{Price: 4000}, nil, 0},
+		{"previous price unknown", Rules{DropPercent: 5}, ps5, 3600, &item.PriceObservation{}, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier := &recorder{}
+			e := NewEvaluator(tt.rules, notifier, NewMemoryDeduper())
+
+			events, err := e.Evaluate(tt.itm, "Amazon", sources.Offer{Price: tt.price, URL: "https://amazon.com.br/dp/B0"}, tt.previous)
+			if err != nil {
+				t.Fatalf("Evaluate error: %v", err)
+			}
+
+			var kinds []Kind
+			for _, event := range events {
+				kinds = append(kinds, event.Kind)
+				if event.ItemID != tt.itm.ID || event.Price != tt.price {
+					t.Errorf("event = %+v", event)
+				}
+				if event.Kind == KindPriceDrop && math.Abs(event.DropPercent-tt.wantDrop) > 1e-9 {
+					t.Errorf("drop = %v, want %v", event.DropPercent, tt.wantDrop)
+				}
+			}
+			if len(kinds) != len(tt.wantKinds) {
+				t.Fatalf("kinds = %v, want %v", kinds, tt.wantKinds)
+			}
+			for i := range kinds {
+				if kinds[i] != tt.wantKinds[i] {
+					t.Errorf("kinds = %v, want %v", kinds, tt.wantKinds)
+				}
+			}
+			if len(notifier.events) != len(events) {
+				t.Errorf("notified %d events, want %d", len(notifier.events), len(events))
+			}
+		})
+	}
+}
+
+func TestEvaluateDedup(t *testing.T) {
+	ps5 := item.Item{ID: "ps5", Name: "PS5", MaxPrice: 3500}
+	offer := sources.Offer{Price: 3400, URL: "https://amazon.com.br/dp/B0"}
+
+	notifier := &recorder{}
+	e := NewEvaluator(Rules{}, notifier, NewMemoryDeduper())
+
+	steps := []struct {
+		name     string
+		itm      item.Item
+		offer    sources.Offer
+		wantSent int
+	}{
+		{"first time", ps5, offer, 1},
+		{"same deal", ps5, offer, 0},
+		{"renamed item", item.Item{ID: "ps5", Name: "PlayStation 5", MaxPrice: 3500}, offer, 0},
+		{"other item with the same name", item.Item{ID: "ps5-2", Name: "PS5", MaxPrice: 3500}, offer, 1},
+		{"new price", ps5, sources.Offer{Price: 3300, URL: offer.URL}, 1},
+	}
+
+	for _, step := range steps {
+		events, err := e.Evaluate(step.itm, "Amazon", step.offer, nil)
+		if err != nil {
+			t.Fatalf("%s: Evaluate error: %v", step.name, err)
+		}
+		if len(events) != step.wantSent {
+			t.Errorf("%s: sent %d events, want %d", step.name, len(events), step.wantSent)
+		}
+	}
+	if len(notifier.events) != 3 {
+		t.Errorf("notified %d events, want 3", len(notifier.events))
+	}
+}
+
+func TestEvaluateMarksOnlyNotifiedEvents(t *testing.T) {
+	ps5 := item.Item{ID: "ps5", Name: "PS5", MaxPrice: 3500}
+	offer := sources.Offer{Price: 3400}
+
+	tests := []struct {
+		name     string
+		notifier Notifier
+		wantErr  bool
+	}{
+		{"no notifier", nil, false},
+		{"empty notifiers", MultiNotifier{}, false},
+		{"failing notifier", &recorder{err: errors.New("offline")}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deduper := NewMemoryDeduper()
+			e := NewEvaluator(Rules{}, tt.notifier, deduper)
+
+			for run := 0; run < 2; run++ {
+				events, err := e.Evaluate(ps5, "Amazon", offer, nil)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("Evaluate error = %v, want error %v", err, tt.wantErr)
+				}
+				if !tt.wantErr && len(events) != 1 {
+					t.Errorf("run %d returned %d events, want 1", run, len(events))
+				}
+			}
+			if len(deduper.sent) != 0 {
+				t.Errorf("deduper marked %v", deduper.sent)
+			}
+		})
+	}
+}
+
+func TestNewEvaluatorFromConfigStateFile(t *testing.T) {
+	defer viper.Reset()
+	defer SetBaseDir("")
+
+	dir := t.TempDir()
+	SetBaseDir(dir)
+
+	tests := []struct {
+		stateFile string
+		want      string
+	}{
+		{"", filepath.Join(dir, defaultStateFile)},
+		{"state/alerts.json", filepath.Join(dir, "state", "alerts.json")},
+		{filepath.Join(dir, "abs.json"), filepath.Join(dir, "abs.json")},
+	}
+
+	for _, tt := range tests {
+		viper.Set("alerts.state_file", tt.stateFile)
+		deduper, ok := NewEvaluatorFromConfig().deduper.(*FileDeduper)
+		if !ok || deduper.filePath != tt.want {
+			t.Errorf("state file %q resolved to %+v, want %s", tt.stateFile, deduper, tt.want)
+		}
+	}
+}
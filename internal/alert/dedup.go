@@ -0,0 +1,119 @@
+package alert
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// Deduper remembers the events already sent
+type Deduper interface {
+	// Seen reports whether the event key was already sent
+	Seen(key string) (bool, error)
+	// Mark records that the event key was sent at the given time
+	Mark(key string, at time.Time) error
+}
+
+// MemoryDeduper remembers the events sent during the process lifetime
+type MemoryDeduper struct {
+	mu   sync.Mutex
+	sent map[string]time.Time
+}
+
+// NewMemoryDeduper creates an empty in-memory deduper
+func NewMemoryDeduper() *MemoryDeduper {
+	return &MemoryDeduper{sent: make(map[string]time.Time)}
+}
+
+// Seen reports whether the key was marked
+func (d *MemoryDeduper) Seen(key string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	_, ok := d.sent[key]
+	return ok, nil
+}
+
+// Mark records the key
+func (d *MemoryDeduper) Mark(key string, at time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.sent[key] = at
+	return nil
+}
+
+// FileDeduper remembers the events sent in a JSON file so deals are reported
+// once across runs. Keys older than the retention are forgotten, reporting the
+// deal again if it is still available.
+type FileDeduper struct {
+	mu        sync.Mutex
+	filePath  string
+	retention time.Duration
+}
+
+// NewFileDeduper creates a deduper stored at filePath forgetting keys after retention
+func NewFileDeduper(filePath string, retention time.Duration) *FileDeduper {
+	return &FileDeduper{filePath: filePath, retention: retention}
+}
+
+// Seen reports whether the key was marked within the retention
+func (d *FileDeduper) Seen(key string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	sent, err := d.load()
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := sent[key]
+	return ok, nil
+}
+
+// Mark records the key and drops the expired ones
+func (d *FileDeduper) Mark(key string, at time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	sent, err := d.load()
+	if err != nil {
+		return err
+	}
+	sent[key] = at
+
+	data, err := json.MarshalIndent(sent, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(d.filePath, data, 0600)
+}
+
+// load reads the keys that have not expired
+func (d *FileDeduper) load() (map[string]time.Time, error) {
+	sent := make(map[string]time.Time)
+
+	data, err := os.ReadFile(d.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return sent, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &sent); err != nil {
+		return nil, err
+	}
+
+	if d.retention > 0 {
+		for key, at := range sent {
+			if time.Since(at) > d.retention {
+				delete(sent, key)
+			}
+		}
+	}
+
+	return sent, nil
+}
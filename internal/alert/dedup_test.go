@@ -0,0 +1,71 @@
+package alert
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileDeduper(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "alerts_sent.json")
+	now := time.Now()
+
+	d := NewFileDeduper(filePath, time.Hour)
+	marks := map[string]time.Time{
+		"recent": now.Add(-time.Minute),
+		"old":    now.Add(-2 * time.Hour),
+	}
+	for key, at := range marks {
+		if err := d.Mark(key, at); err != nil {
+			t.Fatalf("Mark(%s) error: %v", key, err)
+		}
+	}
+
+	// a new deduper reads the keys written by the first one
+	reopened := NewFileDeduper(filePath, time.Hour)
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"recent", true},
+		{"old", false},
+		{"unknown", false},
+	}
+	for _, tt := range tests {
+		seen, err := reopened.Seen(tt.key)
+		if err != nil {
+			t.Fatalf("Seen(%s) error: %v", tt.key, err)
+		}
+		if seen != tt.want {
+			t.Errorf("Seen(%s) = %v, want %v", tt.key, seen, tt.want)
+		}
+	}
+
+	// expired keys are dropped from the file on the next mark
+	if err := reopened.Mark("new", now); err != nil {
+		t.Fatal(err)
+	}
+	forever := NewFileDeduper(filePath, 0)
+	if seen, _ := forever.Seen("old"); seen {
+		t.Error("expired key kept in the file")
+	}
+	if seen, _ := forever.Seen("recent"); !seen {
+		t.Error("recent key dropped from the file")
+	}
+}
+
+func TestFileDeduperErrors(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "alerts_sent.json")
+	if err := os.WriteFile(filePath, []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	d := NewFileDeduper(filePath, time.Hour)
+	if _, err := d.Seen("key"); err == nil {
+		t.Error("Seen expected an error for an invalid file")
+	}
+	if err := d.Mark("key", time.Now()); err == nil {
+		t.Error("Mark expected an error for an invalid file")
+	}
+}
@@ -0,0 +1,131 @@
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/spf13/viper"
+)
+
+// Notifier delivers alert events
+type Notifier interface {
+	Notify(event Event) error
+}
+
+// NotifierFunc adapts a function to the Notifier interface
+type NotifierFunc func(event Event) error
+
+// Notify calls f(event)
+func (f NotifierFunc) Notify(event Event) error {
+	return f(event)
+}
+
+// WriterNotifier writes one line per event
+type WriterNotifier struct {
+	w io.Writer
+}
+
+// NewWriterNotifier creates a notifier writing to w
+func NewWriterNotifier(w io.Writer) *WriterNotifier {
+	return &WriterNotifier{w: w}
+}
+
+// Notify writes the event message
+func (n *WriterNotifier) Notify(event Event) error {
+	_, err := fmt.Fprintf(n.w, "%s [%s] %s\n", event.At.Format("2006-01-02 15:04:05"), event.Kind, event.Message())
+	return err
+}
+
+// FileNotifier appends one line per event to a file
+type FileNotifier struct {
+	filePath string
+}
+
+// NewFileNotifier creates a notifier appending to the file at filePath
+func NewFileNotifier(filePath string) *FileNotifier {
+	return &FileNotifier{filePath: filePath}
+}
+
+// Notify appends the event message to the file
+func (n *FileNotifier) Notify(event Event) error {
+	file, err := os.OpenFile(n.filePath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return NewWriterNotifier(file).Notify(event)
+}
+
+// CommandNotifier runs a shell command per event, the event fields are
+// passed in WISHLIST_ALERT_* environment variables
+type CommandNotifier struct {
+	command string
+}
+
+// NewCommandNotifier creates a notifier running command with sh -c
+func NewCommandNotifier(command string) *CommandNotifier {
+	return &CommandNotifier{command: command}
+}
+
+// Notify runs the command
+func (n *CommandNotifier) Notify(event Event) error {
+	cmd := exec.Command("sh", "-c", n.command)
+	cmd.Env = append(os.Environ(),
+		"WISHLIST_ALERT_KIND="+string(event.Kind),
+		"WISHLIST_ALERT_ITEM="+event.ItemName,
+		"WISHLIST_ALERT_ITEM_ID="+event.ItemID,
+		"WISHLIST_ALERT_SOURCE="+event.Source,
+		"WISHLIST_ALERT_PRICE="+strconv.FormatFloat(event.Price, 'f', 2, 64),
+		"WISHLIST_ALERT_PREVIOUS_PRICE="+strconv.FormatFloat(event.PreviousPrice, 'f', 2, 64),
+		"WISHLIST_ALERT_MAX_PRICE="+strconv.FormatFloat(event.MaxPrice, 'f', 2, 64),
+		"WISHLIST_ALERT_URL="+event.URL,
+		"WISHLIST_ALERT_SELLER="+event.Seller,
+		"WISHLIST_ALERT_MESSAGE="+event.Message(),
+	)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, output)
+	}
+	return nil
+}
+
+// MultiNotifier sends every event to all of its notifiers
+type MultiNotifier []Notifier
+
+// Notify sends the event to each notifier and joins their errors
+func (m MultiNotifier) Notify(event Event) error {
+	var errs []error
+	for _, n := range m {
+		if err := n.Notify(event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NotifierFromConfig builds the notifiers set in the alerts section of the
+// config, it returns nil when none is set:
+//
+//	alerts:
+//	  file: alerts.log
+//	  command: notify-send "$WISHLIST_ALERT_MESSAGE"
+func NotifierFromConfig() Notifier {
+	var notifiers MultiNotifier
+
+	if filePath := viper.GetString("alerts.file"); filePath != "" {
+		notifiers = append(notifiers, NewFileNotifier(filePath))
+	}
+	if command := viper.GetString("alerts.command"); command != "" {
+		notifiers = append(notifiers, NewCommandNotifier(command))
+	}
+
+	if len(notifiers) == 0 {
+		return nil
+	}
+	return notifiers
+}
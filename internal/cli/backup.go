@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/WellyngtonF/WishListCLI/internal/output"
+)
+
+// backup runs the list and restore subcommands
+func (a *App) backup(args []string) error {
+	if len(args) == 0 {
+		return usageErrorf("expected list or restore")
+	}
+
+	switch args[0] {
+	case "list":
+		return a.backupList(args[1:])
+	case "restore":
+		return a.backupRestore(args[1:])
+	default:
+		return usageErrorf("unknown backup command %q, expected list or restore", args[0])
+	}
+}
+
+func (a *App) backupList(args []string) error {
+	fs := a.newFlagSet("backup")
+	format := formatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	backups, err := a.repo.ListBackups()
+	if err != nil {
+		return err
+	}
+
+	list := make([]output.Backup, 0, len(backups))
+	for _, backup := range backups {
+		list = append(list, output.NewBackup(backup))
+	}
+	return output.WriteBackups(a.stdout, *format, list)
+}
+
+func (a *App) backupRestore(args []string) error {
+	fs := a.newFlagSet("backup")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageErrorf("expected one backup ID")
+	}
+
+	replaced, err := a.repo.RestoreBackup(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "restored backup %s\n", fs.Arg(0))
+	if replaced != nil {
+		fmt.Fprintf(a.stdout, "the previous wishlist was saved as backup %s\n", replaced.ID)
+	}
+	return nil
+}
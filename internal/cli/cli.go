@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/WellyngtonF/WishListCLI/internal/output"
+	"github.com/WellyngtonF/WishListCLI/internal/repository"
+)
+
+// Exit codes returned by Run
+const (
+	ExitOK    = 0
+	ExitError = 1
+	ExitUsage = 2
+)
+
+// App runs the non-interactive subcommands
+type App struct {
+	repo   *repository.Repository
+	stdout io.Writer
+	stderr io.Writer
+	// file is the wishlist file of the repository, never overwritten by export
+	file string
+}
+
+// command is a subcommand of the CLI
+type command struct {
+	usage       string
+	description string
+	run         func(a *App, args []string) error
+}
+
+// commands maps each subcommand name to its implementation
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"add":    {"add -name NAME [flags]", "Add an item to the wishlist", (*App).add},
+		"list":   {"list [-search TEXT] [-sort FIELD] [flags]", "List the wishlist items", (*App).list},
+		"show":   {"show [-format FORMAT] ITEM", "Show an item and its latest prices", (*App).show},
+		"update": {"update ITEM [flags]", "Update the fields given as flags, -name renames", (*App).update},
+		"delete": {"delete ITEM...", "Delete items from the wishlist", (*App).delete},
+		"scrape": {"scrape [-format FORMAT] [ITEM...]", "Scrape the prices of all or the given items", (*App).scrape},
+		"export": {"export [-format FORMAT] [-output FILE]", "Export the wishlist with its latest prices", (*App).export},
+		"import": {"import [-on-conflict POLICY] [-dry-run] [flags] FILE", "Import items from CSV, JSON or YAML", (*App).importItems},
+		"backup": {"backup list | backup restore ID", "List the automatic backups or restore one", (*App).backup},
+	}
+}
+
+// usageError is returned for invalid arguments, it exits with ExitUsage
+type usageError struct {
+	msg string
+	// reported is set when the flag package already printed the error
+	reported bool
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usageErrorf(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// New creates the CLI writing results to stdout and errors to stderr
+func New(repo *repository.Repository, stdout, stderr io.Writer) *App {
+	return &App{
+		repo:   repo,
+		stdout: stdout,
+		stderr: stderr,
+	}
+}
+
+// WithFile sets the wishlist file the repository is stored in
+func (a *App) WithFile(path string) *App {
+	a.file = path
+	return a
+}
+
+// Run runs the subcommand in args[0] and returns the process exit code
+func (a *App) Run(args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		PrintUsage(a.stdout)
+		return ExitOK
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(a.stderr, "unknown command %q\n\n", args[0])
+		PrintUsage(a.stderr)
+		return ExitUsage
+	}
+
+	err := cmd.run(a, args[1:])
+
+	var usageErr *usageError
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, flag.ErrHelp):
+		return ExitOK
+	case errors.As(err, &usageErr):
+		if !usageErr.reported {
+			fmt.Fprintf(a.stderr, "%v\nusage: wishlist %s\n", err, cmd.usage)
+		}
+		return ExitUsage
+	case errors.Is(err, errPartialFailure):
+		return ExitError
+	default:
+		fmt.Fprintf(a.stderr, "error: %v\n", err)
+		return ExitError
+	}
+}
+
+// PrintUsage writes the list of commands to w
+func PrintUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: wishlist [-file PATH] [command] [flags]")
+	fmt.Fprintln(w, "\nWithout a command the interactive interface is started.")
+	fmt.Fprintln(w, "\nOptions:")
+	fmt.Fprintln(w, "  -file PATH   wishlist file, .db, .sqlite and .sqlite3 files use SQLite (default wishlist.csv)")
+	fmt.Fprintln(w, "\nCommands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-32s %s\n", commands[name].usage, commands[name].description)
+	}
+
+	fmt.Fprintln(w, "\nITEM is an item name or ID. Run 'wishlist COMMAND -h' for the flags of a command.")
+}
+
+// newFlagSet creates the flag set of a subcommand, errors are reported by Run
+func (a *App) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "usage: wishlist %s\n", commands[name].usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// formatFlag registers the -format flag of the commands printing results
+func formatFlag(fs *flag.FlagSet) *output.Format {
+	format := output.FormatTable
+	fs.Var(&format, "format", "output `FORMAT`: table, json or ndjson")
+	return &format
+}
+
+// parseFlags parses the flags, turning parse errors into usage errors
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{msg: err.Error(), reported: true}
+	}
+	return nil
+}
+
+// splitList splits a comma separated flag value ignoring empty entries
+func splitList(value string) []string {
+	var list []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,313 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	"github.com/WellyngtonF/WishListCLI/internal/output"
+	"github.com/WellyngtonF/WishListCLI/internal/persistence"
+	"github.com/WellyngtonF/WishListCLI/internal/repository"
+)
+
+// newTestApp creates the CLI on an empty wishlist in a temporary directory
+func newTestApp(t *testing.T) (*App, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "wishlist.csv")
+	store, err := persistence.Open(file, persistence.BackupConfig{Keep: 5})
+	if err != nil {
+		t.Fatalf("persistence.Open() error = %v", err)
+	}
+	repo := repository.New(store).
+		WithHistory(persistence.NewCSVHistoryStore(filepath.Join(dir, "price_history.csv"))).
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	var stdout, stderr bytes.Buffer
+	return New(repo, &stdout, &stderr).WithFile(file), &stdout, &stderr
+}
+
+// run runs the CLI and fails the test unless it exits with want
+func run(t *testing.T, app *App, stdout, stderr *bytes.Buffer, want int, args ...string) {
+	t.Helper()
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := app.Run(args); code != want {
+		t.Fatalf("Run(%q) = %d, want %d\nstdout: %s\nstderr: %s", args, code, want, stdout, stderr)
+	}
+}
+
+func TestRunExitCodes(t *testing.T) {
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{nil, ExitOK},
+		{[]string{"help"}, ExitOK},
+		{[]string{"list", "-h"}, ExitOK},
+		{[]string{"list"}, ExitOK},
+		{[]string{"add", "-name", "Console"}, ExitOK},
+		{[]string{"show", "Console"}, ExitOK},
+		{[]string{"update", "Console", "-max-price", "3000"}, ExitOK},
+
+		{[]string{"unknown"}, ExitUsage},
+		{[]string{"list", "-nope"}, ExitUsage},
+		{[]string{"list", "-format", "xml"}, ExitUsage},
+		{[]string{"list", "-sort", "color"}, ExitUsage},
+		{[]string{"list", "-limit", "-1"}, ExitUsage},
+		{[]string{"list", "-updated-since", "yesterday"}, ExitUsage},
+		{[]string{"add"}, ExitUsage},
+		{[]string{"add", "-name", "Mouse", "-sources", "Nowhere"}, ExitUsage},
+		{[]string{"add", "-name", "Mouse", "-source-url", "Amazon"}, ExitUsage},
+		{[]string{"add", "-name", "Mouse", "-source-url", "Amazon=https://example.com/mouse"}, ExitUsage},
+		{[]string{"show"}, ExitUsage},
+		{[]string{"show", "a", "b"}, ExitUsage},
+		{[]string{"update", "-name", "Other"}, ExitUsage},
+		{[]string{"update", "Console", "extra"}, ExitUsage},
+		{[]string{"update", "Console", "-name", " "}, ExitUsage},
+		{[]string{"delete"}, ExitUsage},
+
+		{[]string{"show", "Missing"}, ExitError},
+		{[]string{"update", "Missing", "-max-price", "1"}, ExitError},
+		{[]string{"delete", "Missing"}, ExitError},
+		{[]string{"delete", "Console", "Missing"}, ExitError},
+	}
+
+	// the cases run in order on the same wishlist
+	app, stdout, stderr := newTestApp(t)
+	for _, tt := range tests {
+		stdout.Reset()
+		stderr.Reset()
+		if got := app.Run(tt.args); got != tt.want {
+			t.Errorf("Run(%q) = %d, want %d\nstderr: %s", tt.args, got, tt.want, stderr)
+		}
+		if tt.want == ExitUsage && stderr.Len() == 0 {
+			t.Errorf("Run(%q) exited with a usage error without reporting it", tt.args)
+		}
+	}
+}
+
+func TestRunListJSON(t *testing.T) {
+	app, stdout, stderr := newTestApp(t)
+
+	run(t, app, stdout, stderr, ExitOK, "list", "-format", "json")
+	if got := strings.TrimSpace(stdout.String()); got != "[]" {
+		t.Errorf("list of an empty wishlist = %s, want []", got)
+	}
+
+	run(t, app, stdout, stderr, ExitOK, "add", "-name", "Console", "-max-price", "3000", "-category", "Games")
+	run(t, app, stdout, stderr, ExitOK, "add", "-name", "Controller", "-max-price", "400", "-category", "Games",
+		"-source-url", "Amazon=https://www.amazon.com.br/dp/B0TEST")
+	run(t, app, stdout, stderr, ExitOK, "add", "-name", "Desk", "-max-price", "900")
+
+	run(t, app, stdout, stderr, ExitOK, "list", "-format", "json", "-category", "Games", "-sort", "max_price")
+	var items []output.Item
+	if err := json.Unmarshal(stdout.Bytes(), &items); err != nil {
+		t.Fatalf("list -format json is not a JSON array: %v\n%s", err, stdout)
+	}
+	var names []string
+	for _, itm := range items {
+		names = append(names, itm.Name)
+	}
+	if got, want := strings.Join(names, ","), "Controller,Console"; got != want {
+		t.Errorf("list -category Games -sort max_price = %s, want %s", got, want)
+	}
+
+	controller := items[0]
+	if got := controller.SourceURLs["Amazon"]; got != "https://www.amazon.com.br/dp/B0TEST" {
+		t.Errorf("source_urls[Amazon] = %q, want the page given with -source-url", got)
+	}
+	if len(controller.Sources) != 1 || controller.Sources[0] != "Amazon" {
+		t.Errorf("sources = %v, want [Amazon] added by -source-url", controller.Sources)
+	}
+	if controller.CreatedAt.IsZero() || time.Since(controller.CreatedAt) > time.Minute {
+		t.Errorf("created_at = %v, want the time of the add", controller.CreatedAt)
+	}
+
+	// every line of NDJSON is an object and lists are never null
+	run(t, app, stdout, stderr, ExitOK, "list", "-format", "ndjson")
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("list -format ndjson wrote %d lines, want 3\n%s", len(lines), stdout)
+	}
+	for _, line := range lines {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(line), &fields); err != nil {
+			t.Fatalf("ndjson line %s: %v", line, err)
+		}
+		for _, key := range []string{"sources", "required_keywords", "excluded_keywords", "source_urls"} {
+			if string(fields[key]) == "null" || fields[key] == nil {
+				t.Errorf("ndjson line %s: %s = %s, want a list or object", line, key, fields[key])
+			}
+		}
+		var created string
+		if err := json.Unmarshal(fields["created_at"], &created); err != nil {
+			t.Fatalf("created_at %s is not a string: %v", fields["created_at"], err)
+		}
+		if _, err := time.Parse(time.RFC3339, created); err != nil {
+			t.Errorf("created_at %q is not RFC 3339: %v", created, err)
+		}
+	}
+}
+
+func TestRunShowJSON(t *testing.T) {
+	app, stdout, stderr := newTestApp(t)
+
+	run(t, app, stdout, stderr, ExitOK, "add", "-name", "Console", "-sources", "Amazon,ml")
+	run(t, app, stdout, stderr, ExitOK, "show", "-format", "json", "Console")
+
+	var detail output.ItemDetail
+	if err := json.Unmarshal(stdout.Bytes(), &detail); err != nil {
+		t.Fatalf("show -format json is not a JSON object: %v\n%s", err, stdout)
+	}
+	if detail.Name != "Console" {
+		t.Errorf("show Console = %q, want Console", detail.Name)
+	}
+	if len(detail.LatestPrices) != 2 {
+		t.Fatalf("latest_prices = %v, want one per source", detail.LatestPrices)
+	}
+	for _, latest := range detail.LatestPrices {
+		if latest.Price != nil || latest.ObservedAt != nil {
+			t.Errorf("latest price on %s = %v, want null before scraping", latest.Source, latest)
+		}
+	}
+}
+
+func TestRunUpdateAndDelete(t *testing.T) {
+	app, stdout, stderr := newTestApp(t)
+
+	run(t, app, stdout, stderr, ExitOK, "add", "-name", "Console", "-max-price", "3000")
+	run(t, app, stdout, stderr, ExitOK, "update", "Console", "-name", "Console Pro", "-max-price", "3500")
+	run(t, app, stdout, stderr, ExitError, "show", "Console")
+	if !strings.HasPrefix(stderr.String(), "error: ") {
+		t.Errorf("show of a missing item wrote %q, want an error", stderr)
+	}
+
+	run(t, app, stdout, stderr, ExitOK, "show", "-format", "json", "Console Pro")
+	var detail output.ItemDetail
+	if err := json.Unmarshal(stdout.Bytes(), &detail); err != nil {
+		t.Fatalf("show -format json: %v", err)
+	}
+	if detail.MaxPrice != 3500 {
+		t.Errorf("max_price after update = %v, want 3500", detail.MaxPrice)
+	}
+
+	// deleting an existing and a missing item deletes the existing one
+	run(t, app, stdout, stderr, ExitError, "delete", "Console Pro", "Missing")
+	run(t, app, stdout, stderr, ExitOK, "list", "-format", "json")
+	if got := strings.TrimSpace(stdout.String()); got != "[]" {
+		t.Errorf("list after delete = %s, want []", got)
+	}
+}
+
+func TestRunUpdateSourceURLAlias(t *testing.T) {
+	app, stdout, stderr := newTestApp(t)
+
+	// a page saved under an alias of its source, like in a hand edited file
+	err := app.repo.CreateItem(item.Item{
+		Name:            "Console",
+		ScrapingSources: []string{"Amazon"},
+		SourceURLs:      map[string]string{"amazon.com.br": "https://www.amazon.com.br/dp/OLD"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	show := func() map[string]string {
+		run(t, app, stdout, stderr, ExitOK, "show", "-format", "json", "Console")
+		var detail output.ItemDetail
+		if err := json.Unmarshal(stdout.Bytes(), &detail); err != nil {
+			t.Fatalf("show -format json: %v", err)
+		}
+		return detail.SourceURLs
+	}
+
+	run(t, app, stdout, stderr, ExitOK, "update", "Console", "-source-url", "Amazon=https://www.amazon.com.br/dp/NEW")
+	if got, want := show(), map[string]string{"Amazon": "https://www.amazon.com.br/dp/NEW"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("source_urls after replacing the page = %v, want %v", got, want)
+	}
+
+	run(t, app, stdout, stderr, ExitOK, "update", "Console", "-source-url", "amazon.com.br=")
+	if got := show(); len(got) != 0 {
+		t.Errorf("source_urls after removing the page = %v, want none", got)
+	}
+}
+
+func TestRunExport(t *testing.T) {
+	app, stdout, stderr := newTestApp(t)
+	run(t, app, stdout, stderr, ExitOK, "add", "-name", "Console", "-max-price", "3000")
+
+	dir := filepath.Dir(app.file)
+	wishlist, err := os.ReadFile(app.file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the wishlist file is refused however it is named
+	for _, path := range []string{app.file, filepath.Join(dir, ".", "wishlist.csv")} {
+		run(t, app, stdout, stderr, ExitUsage, "export", "-format", "wishlist", "-output", path)
+	}
+	if err := os.Symlink(app.file, filepath.Join(dir, "link.csv")); err == nil {
+		run(t, app, stdout, stderr, ExitUsage, "export", "-output", filepath.Join(dir, "link.csv"))
+	}
+	if data, _ := os.ReadFile(app.file); !bytes.Equal(data, wishlist) {
+		t.Errorf("wishlist file changed by a refused export:\n%s", data)
+	}
+
+	prices := filepath.Join(dir, "prices.csv")
+	run(t, app, stdout, stderr, ExitOK, "export", "-output", prices)
+	data, err := os.ReadFile(prices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "id,name,") || !strings.Contains(string(data), ",Console,") {
+		t.Errorf("export -output prices.csv = %q, want the comma CSV", data)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".tmp") {
+			t.Errorf("export left the temporary file %s", entry.Name())
+		}
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"72h", now.Add(-72 * time.Hour), false},
+		{"2024-03-01", time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local), false},
+		{"2024-03-01 08:30", time.Date(2024, 3, 1, 8, 30, 0, 0, time.Local), false},
+		{"2024-03-01T08:30:00Z", time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC), false},
+		{"last week", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseSince(tt.value, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSince(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseSince(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	"github.com/WellyngtonF/WishListCLI/internal/output"
+	"github.com/WellyngtonF/WishListCLI/internal/repository"
+	"github.com/WellyngtonF/WishListCLI/internal/scraper/sources"
+)
+
+// itemFlags binds the item.Item fields to flags
+type itemFlags struct {
+	name     string
+	category string
+	producer string
+	maxPrice float64
+	minPrice float64
+	sources  string
+	url      string
+	required string
+	excluded string
+	// sourceURLs holds each -source-url value, SOURCE=URL
+	sourceURLs []string
+}
+
+func (f *itemFlags) register(fs *flag.FlagSet, withName bool) {
+	if withName {
+		fs.StringVar(&f.name, "name", "", "item name")
+	}
+	fs.StringVar(&f.category, "category", "", "item category")
+	fs.StringVar(&f.producer, "producer", "", "item producer")
+	fs.Float64Var(&f.maxPrice, "max-price", 0, "maximum price you want to pay")
+	fs.Float64Var(&f.minPrice, "min-price", 0, "offers below this price are ignored")
+	fs.StringVar(&f.sources, "sources", "", "comma separated scraping sources ("+strings.Join(sources.Names(), ", ")+")")
+	fs.StringVar(&f.url, "url", "", "product URL, scraped instead of searching the source it belongs to")
+	fs.StringVar(&f.required, "required", "", "comma separated keywords every listing must contain")
+	fs.StringVar(&f.excluded, "excluded", "", "comma separated keywords rejecting a listing")
+	fs.Func("source-url", "product page scraped instead of searching a source, as `SOURCE=URL`; repeat for more sources, an empty URL removes it", func(value string) error {
+		f.sourceURLs = append(f.sourceURLs, value)
+		return nil
+	})
+}
+
+// apply copies the flags set on the command line to the item
+func (f *itemFlags) apply(fs *flag.FlagSet, itm *item.Item) error {
+	var err error
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "name":
+			itm.Name = strings.TrimSpace(f.name)
+		case "category":
+			itm.Category = f.category
+		case "producer":
+			itm.Producer = f.producer
+		case "max-price":
+			itm.MaxPrice = f.maxPrice
+		case "min-price":
+			itm.MinPrice = f.minPrice
+		case "sources":
+			itm.ScrapingSources = splitList(f.sources)
+			for _, source := range itm.ScrapingSources {
+				if _, ok := sources.Lookup(source); !ok && err == nil {
+					err = usageErrorf("unsupported source: %s", source)
+				}
+			}
+		case "url":
+			itm.URL = f.url
+		case "required":
+			itm.RequiredKeywords = splitList(f.required)
+		case "excluded":
+			itm.ExcludedKeywords = splitList(f.excluded)
+		case "source-url":
+			// pages saved under an alias are replaced or removed like the others
+			itm.SourceURLs = sources.CanonicalURLs(itm.SourceURLs)
+			for _, value := range f.sourceURLs {
+				if err == nil {
+					err = setSourceURL(itm, value)
+				}
+			}
+		}
+	})
+	return err
+}
+
+// setSourceURL sets the product page of a source from SOURCE=URL, adding the
+// source to the item. An empty URL removes the page.
+func setSourceURL(itm *item.Item, value string) error {
+	name, page, ok := strings.Cut(value, "=")
+	if !ok {
+		return usageErrorf("invalid -source-url %q, expected SOURCE=URL", value)
+	}
+
+	src, ok := sources.Lookup(name)
+	if !ok {
+		return usageErrorf("unsupported source: %s", name)
+	}
+
+	page = strings.TrimSpace(page)
+	if page == "" {
+		delete(itm.SourceURLs, src.Name())
+		return nil
+	}
+	if !src.HandlesURL(page) {
+		return usageErrorf("%s is not a page of %s", page, src.Name())
+	}
+
+	if itm.SourceURLs == nil {
+		itm.SourceURLs = make(map[string]string)
+	}
+	itm.SourceURLs[src.Name()] = page
+
+	for _, source := range itm.ScrapingSources {
+		if s, ok := sources.Lookup(source); ok && s == src {
+			return nil
+		}
+	}
+	itm.ScrapingSources = append(itm.ScrapingSources, src.Name())
+	return nil
+}
+
+func (a *App) add(args []string) error {
+	fs := a.newFlagSet("add")
+	var flags itemFlags
+	flags.register(fs, true)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	var newItem item.Item
+	if err := flags.apply(fs, &newItem); err != nil {
+		return err
+	}
+	if newItem.Name == "" {
+		return usageErrorf("-name is required")
+	}
+
+	if err := a.repo.CreateItem(newItem); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "added %q\n", newItem.Name)
+	return nil
+}
+
+func (a *App) list(args []string) error {
+	fs := a.newFlagSet("list")
+	format := formatFlag(fs)
+	var q repository.Query
+	fs.StringVar(&q.Category, "category", "", "only items of this category")
+	fs.StringVar(&q.Producer, "producer", "", "only items of this producer")
+	fs.StringVar(&q.Source, "source", "", "only items scraped from this source")
+	fs.Float64Var(&q.PriceFrom, "price-from", 0, "only items with a maximum price of at least this")
+	fs.Float64Var(&q.PriceTo, "price-to", 0, "only items with a maximum price of at most this")
+	fs.BoolVar(&q.HasDeal, "deals", false, "only items whose latest price is at or below their maximum price")
+	updatedSince := fs.String("updated-since", "", "only items updated since a date (2006-01-02) or a duration ago (72h)")
+	fs.StringVar(&q.Search, "search", "", "fuzzy search in the item names")
+	sortBy := fs.String("sort", "", "sort by "+sortFieldNames())
+	fs.BoolVar(&q.Desc, "desc", false, "sort in descending order")
+	fs.IntVar(&q.Limit, "limit", 0, "show at most this many items")
+	fs.IntVar(&q.Offset, "offset", 0, "skip this many items")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *sortBy != "" {
+		field, err := repository.ParseSortField(*sortBy)
+		if err != nil {
+			return usageErrorf("%v", err)
+		}
+		q.SortBy = field
+	}
+	if *updatedSince != "" {
+		since, err := parseSince(*updatedSince, time.Now())
+		if err != nil {
+			return err
+		}
+		q.UpdatedSince = since
+	}
+	if q.Limit < 0 || q.Offset < 0 {
+		return usageErrorf("-limit and -offset cannot be negative")
+	}
+
+	items, err := a.repo.Query(q)
+	if err != nil {
+		return err
+	}
+
+	list := make([]output.Item, 0, len(items))
+	for _, itm := range items {
+		list = append(list, output.NewItem(itm))
+	}
+	return output.WriteItems(a.stdout, *format, list)
+}
+
+// sortFieldNames lists the fields accepted by -sort
+func sortFieldNames() string {
+	names := make([]string, len(repository.SortFields))
+	for i, field := range repository.SortFields {
+		names[i] = string(field)
+	}
+	return strings.Join(names, ", ")
+}
+
+// parseSince parses a date, a date and time, or a duration before now
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, usageErrorf("invalid time %q, expected a date like 2006-01-02 or a duration like 72h", value)
+}
+
+func (a *App) show(args []string) error {
+	fs := a.newFlagSet("show")
+	format := formatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageErrorf("expected one item name or ID")
+	}
+
+	itm, err := a.repo.FindItem(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	latest, err := a.repo.LatestPrices(*itm)
+	if err != nil {
+		return err
+	}
+
+	return output.WriteItemDetail(a.stdout, *format, output.NewItemDetail(*itm, latest))
+}
+
+func (a *App) update(args []string) error {
+	fs := a.newFlagSet("update")
+	var flags itemFlags
+	flags.register(fs, true)
+
+	// the item comes before the flags, which stop at the first argument
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		if err := parseFlags(fs, args); err != nil {
+			return err
+		}
+		return usageErrorf("expected the item name or ID before the flags")
+	}
+	ref := args[0]
+
+	if err := parseFlags(fs, args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return usageErrorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	itm, err := a.repo.FindItem(ref)
+	if err != nil {
+		return err
+	}
+
+	if err := flags.apply(fs, itm); err != nil {
+		return err
+	}
+	if itm.Name == "" {
+		return usageErrorf("-name cannot be empty")
+	}
+
+	if err := a.repo.UpdateItem(*itm); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "updated %q\n", itm.Name)
+	return nil
+}
+
+func (a *App) delete(args []string) error {
+	fs := a.newFlagSet("delete")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return usageErrorf("expected at least one item name or ID")
+	}
+
+	// a single backup for all the items
+	var errs []error
+	err := a.repo.WithBackup("delete", func() error {
+		for _, ref := range fs.Args() {
+			itm, err := a.repo.FindItem(ref)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %v", ref, err))
+				continue
+			}
+			if err := a.repo.DeleteItem(itm.ID); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %v", ref, err))
+				continue
+			}
+			fmt.Fprintf(a.stdout, "deleted %q\n", itm.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/WellyngtonF/WishListCLI/internal/alert"
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	"github.com/WellyngtonF/WishListCLI/internal/output"
+	"github.com/WellyngtonF/WishListCLI/internal/scraper"
+)
+
+// errPartialFailure is returned after the results were printed when some of them failed
+var errPartialFailure = errors.New("some jobs failed")
+
+func (a *App) scrape(args []string) error {
+	fs := a.newFlagSet("scrape")
+	workers := fs.Int("workers", 0, "number of concurrent jobs (default from config)")
+	timeout := fs.Duration("timeout", 0, "stop scraping after this duration")
+	format := formatFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	var items []item.Item
+	if fs.NArg() == 0 {
+		all, err := a.repo.ListItems()
+		if err != nil {
+			return err
+		}
+		items = all
+	}
+	for _, ref := range fs.Args() {
+		itm, err := a.repo.FindItem(ref)
+		if err != nil {
+			return fmt.Errorf("%s: %v", ref, err)
+		}
+		items = append(items, *itm)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	runner := scraper.NewRunner(a.repo).
+		SetWorkers(*workers).
+		SetAlerts(alert.NewEvaluatorFromConfig())
+
+	start := time.Now()
+	results := runner.RunJobs(ctx, scraper.Jobs(items))
+
+	failed := 0
+	list := make([]output.ScrapeResult, 0, len(results))
+	for _, result := range results {
+		r := output.NewScrapeResult(result)
+		if r.Status != output.StatusOK {
+			failed++
+		}
+		list = append(list, r)
+	}
+	if err := output.WriteScrapeResults(a.stdout, *format, list); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stderr, "%d jobs, %d failed in %s\n", len(results), failed, time.Since(start).Round(time.Second))
+	if failed > 0 {
+		return errPartialFailure
+	}
+	return nil
+}
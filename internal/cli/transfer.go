@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/WellyngtonF/WishListCLI/internal/export"
+	"github.com/WellyngtonF/WishListCLI/internal/importer"
+	"github.com/WellyngtonF/WishListCLI/internal/output"
+	"github.com/WellyngtonF/WishListCLI/internal/persistence"
+)
+
+func (a *App) export(args []string) error {
+	fs := a.newFlagSet("export")
+	outputPath := fs.String("output", "-", "file to write, - for stdout")
+	formatName := fs.String("format", "", "`FORMAT`: csv, json, markdown, html or wishlist, detected from the -output extension by default")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	format := export.DetectFormat(*outputPath)
+	if *formatName != "" {
+		var err error
+		if format, err = export.ParseFormat(*formatName); err != nil {
+			return usageErrorf("%v", err)
+		}
+	}
+	if *outputPath != "-" && a.file != "" && sameFile(*outputPath, a.file) {
+		return usageErrorf("-output %s is the wishlist file", *outputPath)
+	}
+
+	items, err := a.repo.ListItems()
+	if err != nil {
+		return err
+	}
+
+	report := &export.Report{}
+	if format.NeedsPrices() {
+		if report, err = export.Build(a.repo, items); err != nil {
+			return err
+		}
+	} else {
+		for _, itm := range items {
+			report.Entries = append(report.Entries, export.Entry{Item: itm})
+		}
+	}
+
+	if *outputPath == "-" {
+		return export.Write(a.stdout, format, report)
+	}
+
+	// a failed export leaves an existing file as it was
+	err = persistence.WriteFileAtomic(*outputPath, func(w io.Writer) error {
+		return export.Write(w, format, report)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stderr, "exported %d items to %s\n", len(items), *outputPath)
+	return nil
+}
+
+// sameFile reports whether both paths name the same file, following links
+func sameFile(a, b string) bool {
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	if errA == nil && errB == nil {
+		return os.SameFile(infoA, infoB)
+	}
+
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+func (a *App) importItems(args []string) error {
+	mapping, err := importer.MappingFromViper()
+	if err != nil {
+		return err
+	}
+
+	fs := a.newFlagSet("import")
+	from := fs.String("from", "", "file `FORMAT`: csv, json or yaml, detected from the extension by default")
+	onConflict := fs.String("on-conflict", string(importer.PolicySkip), "what to do with items that already exist: skip, update, rename or error")
+	update := fs.Bool("update", false, "same as -on-conflict update")
+	dryRun := fs.Bool("dry-run", false, "print what would be created, updated and skipped without changing the wishlist")
+	format := formatFlag(fs)
+	delimiter := fs.String("delimiter", "", "CSV column delimiter, detected from the header by default")
+	fs.BoolVar(&mapping.DecimalComma, "decimal-comma", mapping.DecimalComma, "read CSV prices like 1.234,56")
+	columns := fs.String("map", "", "map CSV columns to fields like `HEADER=FIELD,...`, - ignores a column")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageErrorf("expected one file, - for stdin")
+	}
+
+	policy, err := importer.ParsePolicy(*onConflict)
+	if err != nil {
+		return usageErrorf("%v", err)
+	}
+	if *update {
+		policy = importer.PolicyUpdate
+	}
+
+	fileFormat := importer.DetectFormat(fs.Arg(0))
+	if *from != "" {
+		if fileFormat, err = importer.ParseFormat(*from); err != nil {
+			return usageErrorf("%v", err)
+		}
+	}
+	if *delimiter != "" {
+		if err := mapping.SetDelimiter(*delimiter); err != nil {
+			return usageErrorf("invalid -delimiter: %v", err)
+		}
+	}
+	if err := mapping.ParseColumns(*columns); err != nil {
+		return usageErrorf("invalid -map: %v", err)
+	}
+
+	var r io.Reader = os.Stdin
+	if fs.Arg(0) != "-" {
+		file, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	file, err := importer.Read(r, fileFormat, mapping)
+	if err != nil {
+		return err
+	}
+	if len(file.Ignored) > 0 {
+		fmt.Fprintf(a.stderr, "ignored columns: %s\n", strings.Join(file.Ignored, ", "))
+	}
+
+	items, err := a.repo.ListItems()
+	if err != nil {
+		return err
+	}
+
+	plan, err := importer.NewPlan(items, file.Records, policy)
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		actions := make([]output.ImportAction, 0, len(plan.Actions))
+		for _, action := range plan.Actions {
+			actions = append(actions, output.ImportAction{
+				Line:   action.Record.Line,
+				Action: string(action.Kind),
+				ID:     action.Item.ID,
+				Name:   action.Item.Name,
+				Detail: action.Detail,
+			})
+		}
+		if err := output.WriteImportActions(a.stdout, *format, actions); err != nil {
+			return err
+		}
+		if *format != output.FormatTable {
+			return nil
+		}
+	} else if err := importer.Apply(a.repo, plan); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "created %d, updated %d, skipped %d\n", plan.Count(importer.ActionCreate),
+		plan.Count(importer.ActionUpdate), plan.Count(importer.ActionSkip))
+	return nil
+}
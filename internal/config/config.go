@@ -0,0 +1,19 @@
+package config
+
+import "github.com/spf13/viper"
+
+// Load reads config.yaml from the working directory or ./config into viper.
+// The file is optional, a missing file is not an error.
+func Load() error {
+	viper.SetConfigName("config")
+	viper.SetConfigType("yaml")
+	viper.AddConfigPath(".")
+	viper.AddConfigPath("./config")
+
+	if err := viper.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			return err
+		}
+	}
+	return nil
+}
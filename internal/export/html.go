@@ -0,0 +1,60 @@
+package export
+
+import (
+	"html/template"
+	"io"
+)
+
+// htmlReport is a single page with its styles inline, so it can be sent or
+// attached as one file
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"price": formatPrice,
+	"delta": formatDelta,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Wishlist</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+h1 { margin-bottom: 0.2rem; }
+.summary { color: #666; margin-top: 0; }
+table { border-collapse: collapse; width: 100%; }
+th, td { padding: 0.4rem 0.6rem; border-bottom: 1px solid #ddd; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; }
+td.num { text-align: right; white-space: nowrap; }
+tr.deal { background: #eaf7ea; }
+.below { color: #1a7f37; }
+.above { color: #b42318; }
+.muted { color: #888; font-size: 0.85em; }
+ul { margin: 0; padding-left: 1rem; }
+</style>
+</head>
+<body>
+<h1>Wishlist</h1>
+<p class="summary">Generated {{.GeneratedAt.Format "2006-01-02 15:04"}}, {{len .Entries}} items, {{.Deals}} at or below their maximum price.</p>
+<table>
+<thead>
+<tr><th>Item</th><th>Max price</th><th>Best price</th><th>Difference</th><th>Best source</th><th>Latest prices</th></tr>
+</thead>
+<tbody>
+{{- range .Entries}}
+<tr{{if .Deal}} class="deal"{{end}}>
+<td>{{.Item.Name}}{{if or .Item.Producer .Item.Category}}<br><span class="muted">{{.Item.Producer}}{{if and .Item.Producer .Item.Category}} · {{end}}{{.Item.Category}}</span>{{end}}</td>
+<td class="num">{{if gt .Item.MaxPrice 0.0}}{{price .Item.MaxPrice}}{{else}}-{{end}}</td>
+<td class="num">{{with .Best}}{{price .Price}}{{else}}-{{end}}</td>
+<td class="num{{if .Deal}} below{{else if and .Best (gt .Item.MaxPrice 0.0)}} above{{end}}">{{delta .}}</td>
+<td>{{if .Best}}{{if .BestURL}}<a href="{{.BestURL}}">{{.Best.Source}}</a>{{else}}{{.Best.Source}}{{end}}{{if .Best.Seller}}<br><span class="muted">{{.Best.Seller}}</span>{{end}}{{else}}-{{end}}</td>
+<td>{{$latest := .Latest}}{{if .Sources}}<ul>{{range .Sources}}{{with index $latest .}}<li>{{if .URL}}<a href="{{.URL}}">{{.Source}}</a>{{else}}{{.Source}}{{end}}: {{price .Price}} <span class="muted">{{.ObservedAt.Local.Format "2006-01-02"}}</span></li>{{else}}<li>{{.}}: <span class="muted">never scraped</span></li>{{end}}{{end}}</ul>{{else}}-{{end}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+</body>
+</html>
+`))
+
+func writeHTML(w io.Writer, report *Report) error {
+	return htmlReport.Execute(w, report)
+}
@@ -0,0 +1,91 @@
+package export
+
+import (
+	"strings"
+	"time"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	"github.com/WellyngtonF/WishListCLI/internal/repository"
+)
+
+// Report is the wishlist with the latest prices of each item
+type Report struct {
+	GeneratedAt time.Time
+	Entries     []Entry
+}
+
+// Entry is an item with the latest price of each of its sources
+type Entry struct {
+	Item item.Item
+	// Latest is keyed by the trimmed source name, sources never scraped map to nil
+	Latest map[string]*item.PriceObservation
+	// Best is the cheapest of the latest prices, nil if no source was scraped
+	Best *item.PriceObservation
+}
+
+// Build reads the latest prices of the items
+func Build(repo *repository.Repository, items []item.Item) (*Report, error) {
+	report := &Report{GeneratedAt: time.Now()}
+	for _, itm := range items {
+		latest, err := repo.LatestPrices(itm)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := Entry{Item: itm, Latest: latest}
+		for _, source := range entry.Sources() {
+			if obs := latest[source]; obs != nil && (entry.Best == nil || obs.Price < entry.Best.Price) {
+				entry.Best = obs
+			}
+		}
+		report.Entries = append(report.Entries, entry)
+	}
+	return report, nil
+}
+
+// Sources returns the item sources without blanks, in the item order
+func (e Entry) Sources() []string {
+	var list []string
+	for _, source := range e.Item.ScrapingSources {
+		if source = strings.TrimSpace(source); source != "" {
+			list = append(list, source)
+		}
+	}
+	return list
+}
+
+// Delta returns the best price minus the maximum price and its percentage
+// of the maximum price, ok is false without a best price or a maximum price
+func (e Entry) Delta() (delta, percent float64, ok bool) {
+	if e.Best == nil || e.Item.MaxPrice <= 0 {
+		return 0, 0, false
+	}
+
+	delta = e.Best.Price - e.Item.MaxPrice
+	return delta, delta / e.Item.MaxPrice * 100, true
+}
+
+// Deal reports whether the best price is at or below the maximum price
+func (e Entry) Deal() bool {
+	delta, _, ok := e.Delta()
+	return ok && delta <= 0
+}
+
+// BestURL returns the link of the best price, the item URL when there is none
+func (e Entry) BestURL() string {
+	if e.Best != nil && e.Best.URL != "" {
+		return e.Best.URL
+	}
+	return e.Item.URL
+}
+
+// Deals returns the number of entries at or below their maximum price
+func (r *Report) Deals() int {
+	n := 0
+	for _, entry := range r.Entries {
+		if entry.Deal() {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,103 @@
+package export
+
+import (
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	"github.com/WellyngtonF/WishListCLI/internal/persistence"
+	"github.com/WellyngtonF/WishListCLI/internal/repository"
+)
+
+func TestBuild(t *testing.T) {
+	dir := t.TempDir()
+	repo := repository.New(persistence.NewCSVStore(filepath.Join(dir, "wishlist.csv"))).
+		WithHistory(persistence.NewCSVHistoryStore(filepath.Join(dir, "price_history.csv"))).
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	items := []item.Item{
+		{ID: "1", Name: "Console", MaxPrice: 3000, ScrapingSources: []string{"Amazon", " Mercado Livre "}},
+		{ID: "2", Name: "Desk", MaxPrice: 900, ScrapingSources: []string{"Amazon"}},
+		{ID: "3", Name: "Mouse", ScrapingSources: []string{"Amazon"}},
+		{ID: "4", Name: "Chair", MaxPrice: 500, ScrapingSources: []string{"Amazon"}},
+	}
+	observed := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	prices := []item.PriceObservation{
+		{ItemID: "1", ItemName: "Console", Source: "Amazon", Price: 2700, ObservedAt: observed},
+		{ItemID: "1", ItemName: "Console", Source: "Amazon", Price: 2800, ObservedAt: observed.Add(time.Hour)},
+		{ItemID: "1", ItemName: "Console", Source: "Mercado Livre", Price: 3100, ObservedAt: observed},
+		{ItemID: "3", ItemName: "Mouse", Source: "Amazon", Price: 100, ObservedAt: observed},
+		{ItemID: "4", ItemName: "Chair", Source: "Amazon", Price: 550, ObservedAt: observed},
+	}
+	for _, obs := range prices {
+		if err := repo.RecordPrice(obs); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := Build(repo, items)
+	if err != nil {
+		t.Fatalf("Build error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		bestPrice   float64
+		bestSource  string
+		delta       float64
+		percent     float64
+		hasDelta    bool
+		deal        bool
+		neverPriced []string
+	}{
+		// the latest price of each source counts, not the lowest ever seen
+		{name: "Console", bestPrice: 2800, bestSource: "Amazon", delta: -200, percent: -200.0 / 30, hasDelta: true, deal: true},
+		{name: "Desk", neverPriced: []string{"Amazon"}},
+		// without a maximum price there is no difference and no deal
+		{name: "Mouse", bestPrice: 100, bestSource: "Amazon"},
+		{name: "Chair", bestPrice: 550, bestSource: "Amazon", delta: 50, percent: 10, hasDelta: true},
+	}
+
+	if len(report.Entries) != len(tests) {
+		t.Fatalf("Build returned %d entries, want %d", len(report.Entries), len(tests))
+	}
+	for i, tt := range tests {
+		entry := report.Entries[i]
+		if entry.Item.Name != tt.name {
+			t.Errorf("entry %d = %s, want %s", i, entry.Item.Name, tt.name)
+			continue
+		}
+
+		switch {
+		case tt.bestSource == "" && entry.Best != nil:
+			t.Errorf("%s: best = %+v, want nil", tt.name, *entry.Best)
+		case tt.bestSource != "" && (entry.Best == nil || entry.Best.Source != tt.bestSource || entry.Best.Price != tt.bestPrice):
+			t.Errorf("%s: best = %+v, want %.2f on %s", tt.name, entry.Best, tt.bestPrice, tt.bestSource)
+		}
+
+		delta, percent, ok := entry.Delta()
+		if ok != tt.hasDelta || !closeTo(delta, tt.delta) || !closeTo(percent, tt.percent) {
+			t.Errorf("%s: Delta() = %v, %v, %v, want %v, %v, %v", tt.name, delta, percent, ok, tt.delta, tt.percent, tt.hasDelta)
+		}
+		if entry.Deal() != tt.deal {
+			t.Errorf("%s: Deal() = %v, want %v", tt.name, entry.Deal(), tt.deal)
+		}
+		for _, source := range tt.neverPriced {
+			if obs, ok := entry.Latest[source]; !ok || obs != nil {
+				t.Errorf("%s: Latest[%s] = %v, %v, want a nil price", tt.name, source, obs, ok)
+			}
+		}
+	}
+
+	if report.Deals() != 1 {
+		t.Errorf("Deals() = %d, want 1", report.Deals())
+	}
+}
+
+func closeTo(a, b float64) bool {
+	const epsilon = 1e-9
+	return a-b < epsilon && b-a < epsilon
+}
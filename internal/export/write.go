@@ -0,0 +1,217 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	"github.com/WellyngtonF/WishListCLI/internal/output"
+	"github.com/WellyngtonF/WishListCLI/internal/persistence"
+)
+
+// Format of an export file
+type Format string
+
+// Formats accepted by ParseFormat
+const (
+	// FormatWishlist is the semicolon CSV the wishlist is stored in, without prices
+	FormatWishlist Format = "wishlist"
+	// FormatCSV is a comma separated CSV with a header row
+	FormatCSV      Format = "csv"
+	FormatJSON     Format = "json"
+	FormatMarkdown Format = "markdown"
+	// FormatHTML is a single page report without external files
+	FormatHTML Format = "html"
+)
+
+// Formats lists the supported formats
+var Formats = []Format{FormatWishlist, FormatCSV, FormatJSON, FormatMarkdown, FormatHTML}
+
+// ParseFormat returns the format with the given name, md and htm are accepted
+func ParseFormat(name string) (Format, error) {
+	switch name = strings.ToLower(strings.TrimSpace(name)); name {
+	case "md":
+		return FormatMarkdown, nil
+	case "htm":
+		return FormatHTML, nil
+	}
+
+	for _, f := range Formats {
+		if name == string(f) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown export format %q, expected wishlist, csv, json, markdown or html", name)
+}
+
+// DetectFormat returns the format matching the file extension, the comma
+// CSV for other files. The wishlist format is only written when asked for.
+func DetectFormat(filePath string) Format {
+	ext := strings.TrimPrefix(filepath.Ext(filePath), ".")
+	if format, err := ParseFormat(ext); err == nil && format != FormatWishlist {
+		return format
+	}
+	return FormatCSV
+}
+
+// NeedsPrices reports whether the format includes the latest prices
+func (f Format) NeedsPrices() bool {
+	return f != FormatWishlist
+}
+
+// Write writes the report in the given format
+func Write(w io.Writer, format Format, report *Report) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, report)
+	case FormatJSON:
+		return writeJSON(w, report)
+	case FormatMarkdown:
+		return writeMarkdown(w, report)
+	case FormatHTML:
+		return writeHTML(w, report)
+	default:
+		items := make([]item.Item, 0, len(report.Entries))
+		for _, entry := range report.Entries {
+			items = append(items, entry.Item)
+		}
+		return persistence.WriteItemsCSV(w, items)
+	}
+}
+
+// csvColumns are named like the fields read by the importer, the price
+// columns are ignored when the file is imported back
+var csvColumns = []string{
+	"id", "name", "category", "producer", "max_price", "min_price", "sources", "url",
+	"required_keywords", "excluded_keywords", "source_urls",
+	"best_price", "best_source", "best_url", "best_seller", "observed_at", "delta", "delta_percent",
+}
+
+func writeCSV(w io.Writer, report *Report) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvColumns); err != nil {
+		return err
+	}
+
+	for _, entry := range report.Entries {
+		itm := entry.Item
+		row := []string{
+			itm.ID, itm.Name, itm.Category, itm.Producer,
+			formatPrice(itm.MaxPrice), formatPrice(itm.MinPrice),
+			strings.Join(entry.Sources(), ", "), itm.URL,
+			strings.Join(itm.RequiredKeywords, ", "), strings.Join(itm.ExcludedKeywords, ", "),
+			persistence.FormatSourceURLs(itm.SourceURLs),
+			"", "", "", "", "", "", "",
+		}
+		if best := entry.Best; best != nil {
+			row[11] = formatPrice(best.Price)
+			row[12] = best.Source
+			row[13] = best.URL
+			row[14] = best.Seller
+			row[15] = best.ObservedAt.Format("2006-01-02T15:04:05Z07:00")
+		}
+		if delta, percent, ok := entry.Delta(); ok {
+			row[16] = formatPrice(delta)
+			row[17] = strconv.FormatFloat(percent, 'f', 1, 64)
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeJSON(w io.Writer, report *Report) error {
+	doc := output.Report{GeneratedAt: report.GeneratedAt, Items: []output.ReportItem{}}
+	for _, entry := range report.Entries {
+		reportItem := output.ReportItem{
+			ItemDetail: output.NewItemDetail(entry.Item, entry.Latest),
+			Deal:       entry.Deal(),
+		}
+		if entry.Best != nil {
+			best := output.NewLatestPrice(entry.Best.Source, entry.Best)
+			reportItem.Best = &best
+		}
+		if delta, percent, ok := entry.Delta(); ok {
+			reportItem.Delta = &delta
+			reportItem.DeltaPercent = &percent
+		}
+		doc.Items = append(doc.Items, reportItem)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+func writeMarkdown(w io.Writer, report *Report) error {
+	fmt.Fprintf(w, "# Wishlist\n\n")
+	fmt.Fprintf(w, "Generated %s, %d items, %d at or below their maximum price (in bold).\n\n",
+		report.GeneratedAt.Format("2006-01-02 15:04"), len(report.Entries), report.Deals())
+	fmt.Fprintln(w, "| Item | Category | Max price | Best price | Difference | Best source | Seen |")
+	fmt.Fprintln(w, "| --- | --- | ---: | ---: | ---: | --- | --- |")
+
+	for _, entry := range report.Entries {
+		itm := entry.Item
+		best, source, seen := "-", "-", "-"
+		if entry.Best != nil {
+			best = formatPrice(entry.Best.Price)
+			if entry.Deal() {
+				best = "**" + best + "**"
+			}
+			source = markdownCell(entry.Best.Source)
+			if url := entry.BestURL(); url != "" {
+				source = fmt.Sprintf("[%s](%s)", markdownLinkText(entry.Best.Source), markdownURL(url))
+			}
+			seen = entry.Best.ObservedAt.Local().Format("2006-01-02")
+		}
+
+		maxPrice := "-"
+		if itm.MaxPrice > 0 {
+			maxPrice = formatPrice(itm.MaxPrice)
+		}
+
+		_, err := fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s | %s |\n", markdownCell(itm.Name),
+			markdownCell(itm.Category), maxPrice, best, formatDelta(entry), source, seen)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markdownCell escapes the characters ending a table cell or a line
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func markdownLinkText(text string) string {
+	text = strings.NewReplacer("[", `\[`, "]", `\]`).Replace(text)
+	return markdownCell(text)
+}
+
+func markdownURL(url string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "|", "%7C").Replace(url)
+}
+
+// formatPrice writes prices with two decimals like the tables of the output package
+func formatPrice(price float64) string {
+	return strconv.FormatFloat(price, 'f', 2, 64)
+}
+
+// formatDelta writes the difference to the maximum price like "-200.10 (-5.7%)"
+func formatDelta(entry Entry) string {
+	delta, percent, ok := entry.Delta()
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf("%+.2f (%+.1f%%)", delta, percent)
+}
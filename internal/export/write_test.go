@@ -0,0 +1,176 @@
+package export
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/WellyngtonF/WishListCLI/internal/importer"
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+)
+
+// testReport has a deal with a product page and an item never scraped
+func testReport() *Report {
+	console := &item.PriceObservation{
+		ItemID:     "c1",
+		ItemName:   "Console",
+		Source:     "Amazon",
+		Price:      2800,
+		URL:        "https://www.amazon.com.br/dp/B0C",
+		Seller:     "Amazon",
+		ObservedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	return &Report{
+		GeneratedAt: time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC),
+		Entries: []Entry{
+			{
+				Item: item.Item{
+					ID:               "c1",
+					Name:             "Console",
+					Category:         "Games",
+					Producer:         "Sony",
+					MaxPrice:         3000,
+					MinPrice:         1000,
+					ScrapingSources:  []string{"Amazon", "Mercado Livre"},
+					RequiredKeywords: []string{"ps5"},
+					ExcludedKeywords: []string{"controle", "capa"},
+					SourceURLs:       map[string]string{"Amazon": "https://www.amazon.com.br/dp/B0C"},
+				},
+				Latest: map[string]*item.PriceObservation{"Amazon": console, "Mercado Livre": nil},
+				Best:   console,
+			},
+			{
+				Item: item.Item{
+					ID:              "d2",
+					Name:            "Desk | Oak",
+					MaxPrice:        900,
+					ScrapingSources: []string{"Amazon"},
+					URL:             "https://example.com/desk",
+				},
+				Latest: map[string]*item.PriceObservation{"Amazon": nil},
+			},
+		},
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		path string
+		want Format
+	}{
+		{"-", FormatCSV},
+		{"prices.csv", FormatCSV},
+		{"prices.CSV", FormatCSV},
+		{"wishlist.json", FormatJSON},
+		{"wishlist.md", FormatMarkdown},
+		{"wishlist.markdown", FormatMarkdown},
+		{"wishlist.html", FormatHTML},
+		{"wishlist.htm", FormatHTML},
+		{"wishlist.txt", FormatCSV},
+		// the storage format is only written with -format wishlist
+		{"backup.wishlist", FormatCSV},
+	}
+
+	for _, tt := range tests {
+		if got := DetectFormat(tt.path); got != tt.want {
+			t.Errorf("DetectFormat(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	escaped := &Report{Entries: []Entry{{Item: item.Item{Name: "<script>alert(1)</script>"}}}}
+
+	tests := []struct {
+		format Format
+		report *Report
+		want   []string
+	}{
+		{
+			format: FormatCSV,
+			report: testReport(),
+			want: []string{
+				"id,name,category,producer,max_price,min_price,sources,url,required_keywords,excluded_keywords,source_urls,best_price,best_source,best_url,best_seller,observed_at,delta,delta_percent\n" +
+					`c1,Console,Games,Sony,3000.00,1000.00,"Amazon, Mercado Livre",,ps5,"controle, capa",Amazon=https://www.amazon.com.br/dp/B0C,2800.00,Amazon,https://www.amazon.com.br/dp/B0C,Amazon,2024-05-01T12:00:00Z,-200.00,-6.7` + "\n" +
+					"d2,Desk | Oak,,,900.00,0.00,Amazon,https://example.com/desk,,,,,,,,,,\n",
+			},
+		},
+		{
+			format: FormatMarkdown,
+			report: testReport(),
+			want: []string{
+				"Generated 2024-05-02 12:00, 2 items, 1 at or below their maximum price (in bold).\n",
+				"| Console | Games | 3000.00 | **2800.00** | -200.00 (-6.7%) | [Amazon](https://www.amazon.com.br/dp/B0C) | 2024-05-01 |\n",
+				"| Desk \\| Oak |  | 900.00 | - | - | - | - |\n",
+			},
+		},
+		{
+			format: FormatHTML,
+			report: testReport(),
+			want: []string{
+				"Generated 2024-05-02 12:00, 2 items, 1 at or below their maximum price.",
+				`<tr class="deal">` + "\n<td>Console<br><span class=\"muted\">Sony · Games</span></td>",
+				`<td class="num below">-200.00 (-6.7%)</td>`,
+				`<td><a href="https://www.amazon.com.br/dp/B0C">Amazon</a><br><span class="muted">Amazon</span></td>`,
+				`<li>Mercado Livre: <span class="muted">never scraped</span></li>`,
+				"<tr>\n<td>Desk | Oak</td>\n<td class=\"num\">900.00</td>\n<td class=\"num\">-</td>",
+			},
+		},
+		{
+			format: FormatHTML,
+			report: escaped,
+			want:   []string{"<td>&lt;script&gt;alert(1)&lt;/script&gt;</td>"},
+		},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := Write(&buf, tt.format, tt.report); err != nil {
+			t.Fatalf("Write(%s) error: %v", tt.format, err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("Write(%s) does not contain %q\n%s", tt.format, want, buf.String())
+			}
+		}
+	}
+}
+
+func TestWriteImportsBack(t *testing.T) {
+	report := testReport()
+
+	tests := []struct {
+		format     Format
+		importFrom importer.Format
+	}{
+		{FormatCSV, importer.FormatCSV},
+		{FormatJSON, importer.FormatJSON},
+		{FormatWishlist, importer.FormatCSV},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := Write(&buf, tt.format, report); err != nil {
+			t.Fatalf("Write(%s) error: %v", tt.format, err)
+		}
+
+		// no mapping, the columns are named like the importer fields
+		file, err := importer.Read(&buf, tt.importFrom, importer.Mapping{})
+		if err != nil {
+			t.Fatalf("import of the %s export: %v", tt.format, err)
+		}
+		if len(file.Records) != len(report.Entries) {
+			t.Fatalf("import of the %s export read %d items, want %d", tt.format, len(file.Records), len(report.Entries))
+		}
+
+		for i, record := range file.Records {
+			got, want := record.Item, report.Entries[i].Item
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("import of the %s export: item %d = %+v, want %+v", tt.format, i, got, want)
+			}
+		}
+	}
+}
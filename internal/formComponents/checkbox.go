@@ -0,0 +1,212 @@
+package formComponents
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/awesome-gocui/gocui"
+)
+
+// CheckBox field with a row of options, any number of them can be checked
+type CheckBox struct {
+	*InputField
+	options []string
+	checked map[string]bool
+	cursor  int
+	focused bool
+	ctype   ComponentType
+}
+
+// NewCheckBox new check box label and field, the field grows with the options
+func NewCheckBox(gui *gocui.Gui, labelText string, x, y, labelWidth int) *CheckBox {
+	return &CheckBox{
+		InputField: NewInputField(gui, labelText, x, y, labelWidth, 1).SetEditable(false),
+		checked:    make(map[string]bool),
+		ctype:      TypeCheckBox,
+	}
+}
+
+// AddOptions add options to the row
+func (c *CheckBox) AddOptions(options ...string) *CheckBox {
+	c.options = append(c.options, options...)
+
+	if w := c.field.X + c.optionsWidth() + 1; w > c.field.W {
+		c.field.W = w
+	}
+	return c
+}
+
+// SetChecked check the options, the other ones are unchecked
+func (c *CheckBox) SetChecked(options ...string) *CheckBox {
+	c.checked = make(map[string]bool)
+	for _, option := range options {
+		c.checked[option] = true
+	}
+	c.render()
+	return c
+}
+
+// GetChecked get checked options in the order they were added
+func (c *CheckBox) GetChecked() []string {
+	var checked []string
+	for _, option := range c.options {
+		if c.checked[option] {
+			checked = append(checked, option)
+		}
+	}
+	return checked
+}
+
+// GetOptions get options
+func (c *CheckBox) GetOptions() []string {
+	return c.options
+}
+
+// GetFieldText get checked options separated by commas
+func (c *CheckBox) GetFieldText() string {
+	return strings.Join(c.GetChecked(), ",")
+}
+
+// AddHandler add keybinding
+func (c *CheckBox) AddHandler(key Key, handler Handler) *CheckBox {
+	c.InputField.AddHandler(key, handler)
+	return c
+}
+
+// AddValidate add check box validator, validate receives the checked
+// options separated by commas
+func (c *CheckBox) AddValidate(errMsg string, validate func(value string) bool) *CheckBox {
+	c.InputField.AddValidate(errMsg, validate)
+	return c
+}
+
+// Validate validate checked options
+func (c *CheckBox) Validate() bool {
+	c.field.Validate(c.GetFieldText())
+	return c.field.IsValid()
+}
+
+// GetType get component type
+func (c *CheckBox) GetType() ComponentType {
+	return c.ctype
+}
+
+// Focus focus to check box
+func (c *CheckBox) Focus() {
+	c.focused = true
+	c.Gui.Cursor = false
+	c.Gui.SetCurrentView(c.label.text)
+	c.render()
+}
+
+// UnFocus un focus
+func (c *CheckBox) UnFocus() {
+	c.focused = false
+	c.render()
+}
+
+// Draw draw label and options. Space or a click toggles an option and ←/→
+// move between them.
+func (c *CheckBox) Draw() {
+	keys := Handlers{
+		gocui.KeySpace:      c.toggle,
+		gocui.KeyArrowLeft:  c.move(-1),
+		gocui.KeyArrowRight: c.move(+1),
+		gocui.MouseLeft:     c.click,
+	}
+
+	// handlers added by the user replace the built in ones
+	for key, handler := range keys {
+		if _, ok := c.field.handlers[key]; !ok {
+			c.field.handlers[key] = handler
+		}
+	}
+
+	c.InputField.Draw()
+	c.render()
+}
+
+// optionsWidth width of the options row
+func (c *CheckBox) optionsWidth() int {
+	width := 0
+	for i, option := range c.options {
+		if i > 0 {
+			width += 2
+		}
+		width += len([]rune(option)) + 4
+	}
+	return width
+}
+
+// render write the options, highlighting the one under the cursor
+func (c *CheckBox) render() {
+	v, err := c.Gui.View(c.label.text)
+	if err != nil {
+		return
+	}
+
+	v.Clear()
+	for i, option := range c.options {
+		box := "[ ] "
+		if c.checked[option] {
+			box = "[x] "
+		}
+
+		text := box + option
+		if c.focused && i == c.cursor {
+			text = "\x1b[7m" + text + "\x1b[0m"
+		}
+		if i > 0 {
+			fmt.Fprint(v, "  ")
+		}
+		fmt.Fprint(v, text)
+	}
+}
+
+func (c *CheckBox) toggle(g *gocui.Gui, v *gocui.View) error {
+	if c.cursor >= len(c.options) {
+		return nil
+	}
+
+	option := c.options[c.cursor]
+	c.checked[option] = !c.checked[option]
+	c.render()
+
+	c.field.Validate(c.GetFieldText())
+	if c.field.onChange != nil {
+		c.field.onChange(c.GetFieldText())
+	}
+	return nil
+}
+
+func (c *CheckBox) move(delta int) Handler {
+	return func(g *gocui.Gui, v *gocui.View) error {
+		if cursor := c.cursor + delta; cursor >= 0 && cursor < len(c.options) {
+			c.cursor = cursor
+			c.render()
+		}
+		return nil
+	}
+}
+
+// click toggle the clicked option, the mouse position is used because
+// rendering resets the view cursor
+func (c *CheckBox) click(g *gocui.Gui, v *gocui.View) error {
+	mx, _ := g.MousePosition()
+	x0, _, _, _ := v.Dimensions()
+	cx := mx - x0 - 1
+
+	start := 0
+	for i, option := range c.options {
+		end := start + len([]rune(option)) + 4
+		if cx >= start && cx < end {
+			c.cursor = i
+			c.Focus()
+			return c.toggle(g, v)
+		}
+		start = end + 2
+	}
+
+	c.Focus()
+	return nil
+}
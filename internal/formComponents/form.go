@@ -0,0 +1,305 @@
+package formComponents
+
+import (
+	"fmt"
+
+	"github.com/awesome-gocui/gocui"
+)
+
+// rowHeight is the height of every form row
+const rowHeight = 2
+
+// Form lays out components one per row with the buttons below them, moves
+// the focus between them and binds its keys to their views only, so closing
+// the form removes everything it created
+type Form struct {
+	*gocui.Gui
+	name       string
+	x, y       int
+	labelWidth int
+	components []Component
+	buttons    []*Button
+	onSubmit   func(f *Form) error
+	onCancel   func(f *Form) error
+}
+
+// validatable components validate their value
+type validatable interface {
+	Validate() bool
+}
+
+// clickable components handle mouse clicks themselves
+type clickable interface {
+	click(g *gocui.Gui, v *gocui.View) error
+}
+
+// NewForm new form at x, y with labels of labelWidth
+func NewForm(gui *gocui.Gui, name string, x, y, labelWidth int) *Form {
+	return &Form{
+		Gui:        gui,
+		name:       name,
+		x:          x,
+		y:          y,
+		labelWidth: labelWidth,
+	}
+}
+
+// AddInputField add input field in the next row
+func (f *Form) AddInputField(label string, fieldWidth int) *InputField {
+	input := NewInputField(f.Gui, label, f.x, f.nextY(), f.labelWidth, fieldWidth)
+	f.components = append(f.components, input)
+	return input
+}
+
+// AddSelect add select in the next row
+func (f *Form) AddSelect(label string, fieldWidth int) *Select {
+	sel := NewSelect(f.Gui, label, f.x, f.nextY(), f.labelWidth, fieldWidth)
+	f.components = append(f.components, sel)
+	return sel
+}
+
+// AddCheckBox add check box in the next row
+func (f *Form) AddCheckBox(label string) *CheckBox {
+	checkBox := NewCheckBox(f.Gui, label, f.x, f.nextY(), f.labelWidth)
+	f.components = append(f.components, checkBox)
+	return checkBox
+}
+
+// AddComponent add a component created by the caller, it must be placed
+// at the position returned by NextPosition
+func (f *Form) AddComponent(c Component) *Form {
+	f.components = append(f.components, c)
+	return f
+}
+
+// NextPosition position of the next row and the label width
+func (f *Form) NextPosition() (x, y, labelWidth int) {
+	return f.x, f.nextY(), f.labelWidth
+}
+
+// AddButton add button below the components, Enter and click run the handler
+func (f *Form) AddButton(label string, handler Handler) *Button {
+	button := NewButton(f.Gui, label, 0, 0, len(label)+1).
+		AddHandler(gocui.KeyEnter, handler).
+		AddHandler(gocui.MouseLeft, handler)
+	f.buttons = append(f.buttons, button)
+	return button
+}
+
+// SetSubmit add the submit button, submit is called once every component is
+// valid and its error is shown in the form
+func (f *Form) SetSubmit(label string, submit func(f *Form) error) *Form {
+	f.onSubmit = submit
+	f.AddButton(label, func(g *gocui.Gui, v *gocui.View) error {
+		return f.Submit()
+	})
+	return f
+}
+
+// SetCancel set function called when the form is cancelled with Esc or Ctrl+C
+func (f *Form) SetCancel(cancel func(f *Form) error) *Form {
+	f.onCancel = cancel
+	return f
+}
+
+// GetName get form name
+func (f *Form) GetName() string {
+	return f.name
+}
+
+// GetComponents get components in focus order, buttons excluded
+func (f *Form) GetComponents() []Component {
+	return f.components
+}
+
+// Validate validate every component, all of them show their error
+func (f *Form) Validate() bool {
+	valid := true
+	for _, c := range f.components {
+		if v, ok := c.(validatable); ok && !v.Validate() {
+			valid = false
+		}
+	}
+	return valid
+}
+
+// Submit validate the form and call the submit function, only failing
+// when its error cannot be shown
+func (f *Form) Submit() error {
+	if err := f.SetError(""); err != nil {
+		return err
+	}
+	if !f.Validate() || f.onSubmit == nil {
+		return nil
+	}
+
+	if err := f.onSubmit(f); err != nil {
+		return f.SetError(err.Error())
+	}
+	return nil
+}
+
+// Cancel close the form and call the cancel function
+func (f *Form) Cancel() error {
+	f.Close()
+	if f.onCancel != nil {
+		return f.onCancel(f)
+	}
+	return nil
+}
+
+// SetError show a message next to the buttons, an empty message hides it
+func (f *Form) SetError(msg string) error {
+	name := f.errorViewName()
+	if msg == "" {
+		if err := f.DeleteView(name); err != nil && err != gocui.ErrUnknownView {
+			return err
+		}
+		return nil
+	}
+
+	x, y := f.buttonsEnd()
+	v, err := f.SetView(name, x+2, y, x+len(msg)+4, y+rowHeight, 0)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+
+	v.Frame = false
+	v.FgColor = gocui.ColorRed
+	v.Clear()
+	fmt.Fprint(v, msg)
+	return nil
+}
+
+// Draw draw every component and focus the first one
+func (f *Form) Draw() {
+	f.layoutButtons()
+
+	items := f.items()
+	for _, c := range items {
+		c.AddHandlerOnly(gocui.KeyTab, f.next)
+		c.AddHandlerOnly(gocui.KeyBacktab, f.prev)
+		c.AddHandlerOnly(gocui.KeyArrowDown, f.next)
+		c.AddHandlerOnly(gocui.KeyArrowUp, f.prev)
+		c.AddHandlerOnly(gocui.KeyEsc, f.cancel)
+		c.AddHandlerOnly(gocui.KeyCtrlC, f.cancel)
+
+		switch c.GetType() {
+		case TypeInputField, TypeCheckBox:
+			c.AddHandlerOnly(gocui.KeyEnter, f.submit)
+		}
+		if c.GetType() != TypeButton {
+			c.AddHandlerOnly(gocui.MouseLeft, f.focusClicked)
+		}
+	}
+
+	for _, c := range items {
+		c.Draw()
+	}
+
+	if len(items) > 0 {
+		f.focus(0)
+	}
+}
+
+// Close close every component and the error message
+func (f *Form) Close() {
+	for _, c := range f.items() {
+		c.Close()
+	}
+	f.DeleteView(f.errorViewName())
+}
+
+func (f *Form) nextY() int {
+	return f.y + len(f.components)*rowHeight
+}
+
+// items components and buttons in focus order
+func (f *Form) items() []Component {
+	items := append([]Component(nil), f.components...)
+	for _, b := range f.buttons {
+		items = append(items, b)
+	}
+	return items
+}
+
+// layoutButtons place the buttons in a row below the components
+func (f *Form) layoutButtons() {
+	x, y := f.x, f.nextY()
+	for _, b := range f.buttons {
+		p := b.GetPosition()
+		width := p.W - p.X
+		p.X, p.Y, p.W, p.H = x, y, x+width, y+rowHeight
+		x += width + 2
+	}
+}
+
+// buttonsEnd position after the last button
+func (f *Form) buttonsEnd() (int, int) {
+	if len(f.buttons) == 0 {
+		return f.x, f.nextY()
+	}
+	p := f.buttons[len(f.buttons)-1].GetPosition()
+	return p.W, p.Y
+}
+
+func (f *Form) errorViewName() string {
+	return f.name + "Error"
+}
+
+// current index of the focused item, -1 when the focus is outside the form
+func (f *Form) current() int {
+	v := f.CurrentView()
+	if v == nil {
+		return -1
+	}
+	for i, c := range f.items() {
+		if c.GetLabel() == v.Name() {
+			return i
+		}
+	}
+	return -1
+}
+
+func (f *Form) focus(index int) {
+	items := f.items()
+	if current := f.current(); current >= 0 {
+		items[current].UnFocus()
+	}
+	items[index].Focus()
+}
+
+func (f *Form) next(g *gocui.Gui, v *gocui.View) error {
+	n := len(f.items())
+	f.focus((f.current() + 1) % n)
+	return nil
+}
+
+func (f *Form) prev(g *gocui.Gui, v *gocui.View) error {
+	n := len(f.items())
+	f.focus((f.current() - 1 + n) % n)
+	return nil
+}
+
+func (f *Form) submit(g *gocui.Gui, v *gocui.View) error {
+	return f.Submit()
+}
+
+func (f *Form) cancel(g *gocui.Gui, v *gocui.View) error {
+	return f.Cancel()
+}
+
+// focusClicked focus the clicked component and pass it the click
+func (f *Form) focusClicked(g *gocui.Gui, v *gocui.View) error {
+	for i, c := range f.items() {
+		if c.GetLabel() != v.Name() {
+			continue
+		}
+
+		f.focus(i)
+		if c, ok := c.(clickable); ok {
+			return c.click(g, v)
+		}
+	}
+	return nil
+}
@@ -39,6 +39,7 @@ type Field struct {
 	margin    *Margin
 	mask      bool
 	editable  bool
+	onChange  func(text string)
 	ctype     ComponentType
 	*Position
 	*Attributes
@@ -189,9 +190,21 @@ func (i *InputField) SetMaskKeybinding(key Key) *InputField {
 	return i
 }
 
-// SetText set text
+// SetText set text, a drawn field shows the new text
 func (i *InputField) SetText(text string) *InputField {
 	i.field.text = text
+
+	if v, err := i.Gui.View(i.label.text); err == nil {
+		v.Clear()
+		fmt.Fprint(v, text)
+		v.SetCursor(len([]rune(text)), 0)
+	}
+	return i
+}
+
+// SetOnChange set function called with the text after every edit
+func (i *InputField) SetOnChange(onChange func(text string)) *InputField {
+	i.field.onChange = onChange
 	return i
 }
 
@@ -231,6 +244,12 @@ func (i *InputField) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modif
 		v.MoveCursor(-1, 0)
 	case key == gocui.KeyArrowRight:
 		v.MoveCursor(+1, 0)
+	case key == gocui.KeyDelete:
+		v.EditDelete(false)
+	case key == gocui.KeyHome:
+		v.SetCursor(0, 0)
+	case key == gocui.KeyEnd:
+		v.SetCursor(len([]rune(i.cutNewline(v.Buffer()))), 0)
 	}
 
 	// get field text
@@ -238,6 +257,10 @@ func (i *InputField) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modif
 
 	// validate
 	i.field.Validate(i.GetFieldText())
+
+	if i.field.onChange != nil {
+		i.field.onChange(i.GetFieldText())
+	}
 }
 
 // GetFieldText get input field text
@@ -309,6 +332,7 @@ func (i *InputField) Draw() {
 
 		if i.field.text != "" {
 			fmt.Fprint(v, i.field.text)
+			v.SetCursor(len([]rune(i.field.text)), 0)
 		}
 
 		// focus input field
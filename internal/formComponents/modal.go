@@ -0,0 +1,244 @@
+package formComponents
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/awesome-gocui/gocui"
+)
+
+// Modal dialog with a message and confirm and cancel buttons
+type Modal struct {
+	*gocui.Gui
+	label     string
+	title     string
+	message   string
+	confirm   string
+	cancel    string
+	onConfirm Handler
+	onCancel  Handler
+	focused   int
+	ctype     ComponentType
+	*Position
+	*Attributes
+}
+
+// Indexes of the modal buttons
+const (
+	modalConfirm = iota
+	modalCancel
+)
+
+// NewModal new modal centered on the screen
+func NewModal(gui *gocui.Gui, label, title, message string) *Modal {
+	return &Modal{
+		Gui:     gui,
+		label:   label,
+		title:   title,
+		message: message,
+		confirm: "Confirm",
+		cancel:  "Cancel",
+		focused: modalCancel,
+		Position: &Position{
+			0,
+			0,
+			0,
+			0,
+		},
+		Attributes: &Attributes{
+			textColor:      gocui.ColorWhite,
+			textBgColor:    gocui.ColorDefault,
+			hilightColor:   gocui.ColorBlack,
+			hilightBgColor: gocui.ColorWhite,
+		},
+		ctype: TypeModal,
+	}
+}
+
+// SetConfirm set confirm button label and handler, the modal is closed before the handler runs
+func (m *Modal) SetConfirm(label string, handler Handler) *Modal {
+	m.confirm = label
+	m.onConfirm = handler
+	return m
+}
+
+// SetCancel set cancel button label and handler, the modal is closed before the handler runs
+func (m *Modal) SetCancel(label string, handler Handler) *Modal {
+	m.cancel = label
+	m.onCancel = handler
+	return m
+}
+
+// FocusConfirm focus the confirm button instead of the cancel one
+func (m *Modal) FocusConfirm() *Modal {
+	m.focused = modalConfirm
+	return m
+}
+
+// GetLabel get modal label
+func (m *Modal) GetLabel() string {
+	return m.label
+}
+
+// GetPosition get modal position
+func (m *Modal) GetPosition() *Position {
+	return m.Position
+}
+
+// GetType get component type
+func (m *Modal) GetType() ComponentType {
+	return m.ctype
+}
+
+// Focus focus to modal
+func (m *Modal) Focus() {
+	m.Gui.Cursor = false
+	m.Gui.SetCurrentView(m.label)
+}
+
+// UnFocus un focus
+func (m *Modal) UnFocus() {}
+
+// Draw draw modal on top of the other views
+func (m *Modal) Draw() {
+	lines := strings.Split(m.message, "\n")
+
+	width := len(m.buttons()) + 4
+	for _, line := range lines {
+		if l := len([]rune(line)) + 4; l > width {
+			width = l
+		}
+	}
+	if l := len([]rune(m.title)) + 6; l > width {
+		width = l
+	}
+
+	maxX, maxY := m.Gui.Size()
+	height := len(lines) + 3
+	m.X = (maxX - width) / 2
+	m.Y = (maxY - height) / 2
+	m.W = m.X + width
+	m.H = m.Y + height
+
+	if v, err := m.Gui.SetView(m.label, m.X, m.Y, m.W, m.H, 0); err != nil {
+		if err != gocui.ErrUnknownView {
+			panic(err)
+		}
+
+		v.Title = m.title
+		v.FgColor = m.textColor
+		v.BgColor = m.textBgColor
+	}
+	m.Gui.SetViewOnTop(m.label)
+
+	keys := Handlers{
+		gocui.KeyTab:        m.toggle,
+		gocui.KeyArrowLeft:  m.toggle,
+		gocui.KeyArrowRight: m.toggle,
+		gocui.KeyEnter: func(g *gocui.Gui, v *gocui.View) error {
+			return m.press(m.focused)
+		},
+		gocui.KeyEsc: func(g *gocui.Gui, v *gocui.View) error {
+			return m.press(modalCancel)
+		},
+		'y': func(g *gocui.Gui, v *gocui.View) error {
+			return m.press(modalConfirm)
+		},
+		'n': func(g *gocui.Gui, v *gocui.View) error {
+			return m.press(modalCancel)
+		},
+		gocui.MouseLeft: m.click,
+	}
+
+	for key, handler := range keys {
+		if err := m.Gui.SetKeybinding(m.label, key, gocui.ModNone, handler); err != nil {
+			panic(err)
+		}
+	}
+
+	m.render()
+	m.Focus()
+}
+
+// Close close modal
+func (m *Modal) Close() {
+	if err := m.DeleteView(m.label); err != nil {
+		if err != gocui.ErrUnknownView {
+			panic(err)
+		}
+	}
+
+	m.DeleteKeybindings(m.label)
+}
+
+// AddHandlerOnly modal keys are fixed, handlers are set with SetConfirm and SetCancel
+func (m *Modal) AddHandlerOnly(key Key, handler Handler) {}
+
+// buttons returns the buttons line without colors
+func (m *Modal) buttons() string {
+	return fmt.Sprintf("[ %s ]  [ %s ]", m.confirm, m.cancel)
+}
+
+// render write the message and the buttons, highlighting the focused one
+func (m *Modal) render() {
+	v, err := m.Gui.View(m.label)
+	if err != nil {
+		return
+	}
+
+	v.Clear()
+	for _, line := range strings.Split(m.message, "\n") {
+		fmt.Fprintf(v, " %s\n", line)
+	}
+
+	labels := []string{m.confirm, m.cancel}
+	for i, label := range labels {
+		button := fmt.Sprintf("[ %s ]", label)
+		if i == m.focused {
+			button = "\x1b[7m" + button + "\x1b[0m"
+		}
+		if i > 0 {
+			fmt.Fprint(v, "  ")
+		} else {
+			fmt.Fprint(v, "\n ")
+		}
+		fmt.Fprint(v, button)
+	}
+}
+
+func (m *Modal) toggle(g *gocui.Gui, v *gocui.View) error {
+	m.focused = 1 - m.focused
+	m.render()
+	return nil
+}
+
+// click press the clicked button
+func (m *Modal) click(g *gocui.Gui, v *gocui.View) error {
+	cx, cy := v.Cursor()
+	if cy != len(strings.Split(m.message, "\n"))+1 {
+		return nil
+	}
+
+	confirmWidth := len([]rune(m.confirm)) + 4
+	switch {
+	case cx >= 1 && cx <= confirmWidth:
+		return m.press(modalConfirm)
+	case cx >= confirmWidth+3:
+		return m.press(modalCancel)
+	}
+	return nil
+}
+
+// press close the modal and run the handler of the button
+func (m *Modal) press(button int) error {
+	m.Close()
+
+	handler := m.onCancel
+	if button == modalConfirm {
+		handler = m.onConfirm
+	}
+	if handler == nil {
+		return nil
+	}
+	return handler(m.Gui, nil)
+}
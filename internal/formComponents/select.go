@@ -0,0 +1,295 @@
+package formComponents
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/awesome-gocui/gocui"
+)
+
+// selectListHeight maximum number of options shown by the drop down list
+const selectListHeight = 8
+
+// Select input field whose value is chosen from a drop down list, when it is
+// editable values missing from the list can be typed too
+type Select struct {
+	*InputField
+	options []string
+	// shown options listed by the open drop down list
+	shown []string
+	ctype ComponentType
+}
+
+// NewSelect new select label and field
+func NewSelect(gui *gocui.Gui, labelText string, x, y, labelWidth, fieldWidth int) *Select {
+	return &Select{
+		InputField: NewInputField(gui, labelText, x, y, labelWidth, fieldWidth).SetEditable(false),
+		ctype:      TypeSelect,
+	}
+}
+
+// AddOptions add options to the list
+func (s *Select) AddOptions(options ...string) *Select {
+	s.options = append(s.options, options...)
+	return s
+}
+
+// SetSelected set selected value
+func (s *Select) SetSelected(value string) *Select {
+	s.SetText(value)
+	return s
+}
+
+// GetSelected get selected value
+func (s *Select) GetSelected() string {
+	return s.GetFieldText()
+}
+
+// GetOptions get options
+func (s *Select) GetOptions() []string {
+	return s.options
+}
+
+// SetEditable if editmode is true values can be typed
+func (s *Select) SetEditable(b bool) *Select {
+	s.InputField.SetEditable(b)
+	return s
+}
+
+// AddHandler add keybinding
+func (s *Select) AddHandler(key Key, handler Handler) *Select {
+	s.InputField.AddHandler(key, handler)
+	return s
+}
+
+// AddValidate add select validator
+func (s *Select) AddValidate(errMsg string, validate func(value string) bool) *Select {
+	s.InputField.AddValidate(errMsg, validate)
+	return s
+}
+
+// GetType get component type
+func (s *Select) GetType() ComponentType {
+	return s.ctype
+}
+
+// Focus focus to select
+func (s *Select) Focus() {
+	s.Gui.Cursor = s.field.editable
+	s.Gui.SetCurrentView(s.label.text)
+}
+
+// Draw draw label and field. Enter or a click opens the list, when the
+// select is not editable Space opens it too and ←/→ change the value.
+func (s *Select) Draw() {
+	keys := Handlers{
+		gocui.KeyEnter:  s.toggleList,
+		gocui.MouseLeft: s.click,
+	}
+	if !s.field.editable {
+		keys[gocui.KeySpace] = s.toggleList
+		keys[gocui.KeyArrowLeft] = s.cycle(-1)
+		keys[gocui.KeyArrowRight] = s.cycle(+1)
+	}
+
+	// handlers added by the user replace the built in ones
+	for key, handler := range keys {
+		if _, ok := s.field.handlers[key]; !ok {
+			s.field.handlers[key] = handler
+		}
+	}
+
+	s.InputField.Draw()
+}
+
+// Close close select and its list
+func (s *Select) Close() {
+	s.closeList()
+	s.InputField.Close()
+}
+
+func (s *Select) listName() string {
+	return s.label.text + "List"
+}
+
+func (s *Select) click(g *gocui.Gui, v *gocui.View) error {
+	s.Focus()
+	return s.toggleList(g, v)
+}
+
+func (s *Select) toggleList(g *gocui.Gui, v *gocui.View) error {
+	if _, err := s.Gui.View(s.listName()); err == nil {
+		s.closeList()
+		s.Focus()
+		return nil
+	}
+	return s.openList()
+}
+
+// cycle select the option delta positions away from the current one
+func (s *Select) cycle(delta int) Handler {
+	return func(g *gocui.Gui, v *gocui.View) error {
+		n := len(s.options)
+		if n == 0 {
+			return nil
+		}
+
+		index := s.indexOf(s.options, s.GetFieldText())
+		if index < 0 && delta < 0 {
+			index = 0
+		}
+		s.choose(s.options[((index+delta)%n+n)%n])
+		return nil
+	}
+}
+
+// matches options listed for the current text, an editable select only
+// lists the options containing what was typed
+func (s *Select) matches() []string {
+	text := strings.ToLower(strings.TrimSpace(s.GetFieldText()))
+	if !s.field.editable || text == "" || s.indexOf(s.options, s.GetFieldText()) >= 0 {
+		return s.options
+	}
+
+	var matches []string
+	for _, option := range s.options {
+		if strings.Contains(strings.ToLower(option), text) {
+			matches = append(matches, option)
+		}
+	}
+	return matches
+}
+
+func (s *Select) indexOf(options []string, value string) int {
+	for i, option := range options {
+		if option == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// openList show the options below the field with the current one selected
+func (s *Select) openList() error {
+	s.shown = s.matches()
+	if len(s.shown) == 0 {
+		return nil
+	}
+
+	height := len(s.shown)
+	if height > selectListHeight {
+		height = selectListHeight
+	}
+
+	width := s.field.W - s.field.X
+	for _, option := range s.shown {
+		if l := len([]rune(option)) + 2; l > width {
+			width = l
+		}
+	}
+
+	x, y := s.field.X+s.field.margin.left, s.field.H+s.field.margin.top
+	v, err := s.Gui.SetView(s.listName(), x, y, x+width, y+height+1, 0)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+
+	v.Clear()
+	v.Highlight = true
+	v.FgColor = s.field.textColor
+	v.BgColor = s.field.textBgColor
+	v.SelFgColor = gocui.ColorWhite
+	v.SelBgColor = gocui.ColorBlue
+	for _, option := range s.shown {
+		fmt.Fprintln(v, option)
+	}
+
+	keys := Handlers{
+		gocui.KeyArrowDown: s.moveList(+1),
+		gocui.KeyArrowUp:   s.moveList(-1),
+		gocui.KeyEnter:     s.chooseListed,
+		gocui.MouseLeft:    s.chooseListed,
+		gocui.KeyEsc: func(g *gocui.Gui, v *gocui.View) error {
+			s.closeList()
+			s.Focus()
+			return nil
+		},
+	}
+	for key, handler := range keys {
+		if err := s.Gui.SetKeybinding(s.listName(), key, gocui.ModNone, handler); err != nil {
+			return err
+		}
+	}
+
+	s.Gui.SetViewOnTop(s.listName())
+	s.Gui.Cursor = false
+	s.Gui.SetCurrentView(s.listName())
+
+	index := s.indexOf(s.shown, s.GetFieldText())
+	if index < 0 {
+		index = 0
+	}
+	s.setListIndex(v, index)
+	return nil
+}
+
+// closeList close the drop down list
+func (s *Select) closeList() {
+	if err := s.DeleteView(s.listName()); err != nil {
+		if err != gocui.ErrUnknownView {
+			panic(err)
+		}
+	}
+	s.DeleteKeybindings(s.listName())
+}
+
+func (s *Select) listIndex(v *gocui.View) int {
+	_, cy := v.Cursor()
+	_, oy := v.Origin()
+	return cy + oy
+}
+
+// setListIndex move the list cursor to index, scrolling when it is out of view
+func (s *Select) setListIndex(v *gocui.View, index int) {
+	_, height := v.Size()
+	origin := 0
+	if index >= height {
+		origin = index - height + 1
+	}
+	v.SetOrigin(0, origin)
+	v.SetCursor(0, index-origin)
+}
+
+func (s *Select) moveList(delta int) Handler {
+	return func(g *gocui.Gui, v *gocui.View) error {
+		index := s.listIndex(v) + delta
+		if index < 0 || index >= len(s.shown) {
+			return nil
+		}
+		s.setListIndex(v, index)
+		return nil
+	}
+}
+
+// chooseListed select the option under the list cursor and close the list
+func (s *Select) chooseListed(g *gocui.Gui, v *gocui.View) error {
+	index := s.listIndex(v)
+	if index < 0 || index >= len(s.shown) {
+		return nil
+	}
+
+	s.closeList()
+	s.choose(s.shown[index])
+	s.Focus()
+	return nil
+}
+
+// choose set the value, validating it and notifying the change
+func (s *Select) choose(value string) {
+	s.SetText(value)
+	s.field.Validate(value)
+
+	if s.field.onChange != nil {
+		s.field.onChange(value)
+	}
+}
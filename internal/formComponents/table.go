@@ -0,0 +1,556 @@
+package formComponents
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/awesome-gocui/gocui"
+)
+
+// TableColumn table column title and width
+type TableColumn struct {
+	Title string
+	Width int
+}
+
+// TableRow table row, Key identifies the row when the table is sorted
+type TableRow struct {
+	Key   string
+	Cells []string
+}
+
+// Table scrollable table with row selection and column sorting
+type Table struct {
+	*gocui.Gui
+	label      string
+	columns    []TableColumn
+	rows       []TableRow
+	selected   int
+	offset     int
+	sortColumn int
+	sortDesc   bool
+	sorter     func(column int, desc bool)
+	empty      string
+	multi      bool
+	marked     map[string]bool
+	handlers   Handlers
+	ctype      ComponentType
+	*Position
+	*Attributes
+}
+
+// NewTable new table
+func NewTable(gui *gocui.Gui, label string, x, y, w, h int) *Table {
+	return &Table{
+		Gui:        gui,
+		label:      label,
+		sortColumn: -1,
+		empty:      "No rows",
+		marked:     make(map[string]bool),
+		Position: &Position{
+			x,
+			y,
+			w,
+			h,
+		},
+		Attributes: &Attributes{
+			textColor:      gocui.ColorWhite,
+			textBgColor:    gocui.ColorDefault,
+			hilightColor:   gocui.ColorBlack,
+			hilightBgColor: gocui.ColorWhite,
+		},
+		handlers: make(Handlers),
+		ctype:    TypeTable,
+	}
+}
+
+// AddColumn add column
+func (t *Table) AddColumn(title string, width int) *Table {
+	if len(title)+2 > width {
+		width = len(title) + 2
+	}
+	t.columns = append(t.columns, TableColumn{Title: title, Width: width})
+	return t
+}
+
+// AddHandler add keybinding
+func (t *Table) AddHandler(key Key, handler Handler) *Table {
+	t.handlers[key] = handler
+	return t
+}
+
+// SetEmptyText set text displayed without rows
+func (t *Table) SetEmptyText(text string) *Table {
+	t.empty = text
+	return t
+}
+
+// SetSorter sort rows with the sorter instead of the table, it is called
+// with the column chosen, -1 for the order rows were set, and is expected to
+// set the rows again in the new order
+func (t *Table) SetSorter(sorter func(column int, desc bool)) *Table {
+	t.sorter = sorter
+	return t
+}
+
+// SetMultiSelect allow marking rows with Space, a mark column is added before the first one
+func (t *Table) SetMultiSelect(b bool) *Table {
+	t.multi = b
+	return t
+}
+
+// SetRows set rows, the selected row is kept when its key is still present
+func (t *Table) SetRows(rows []TableRow) *Table {
+	key := ""
+	if row, ok := t.GetSelected(); ok {
+		key = row.Key
+	}
+
+	t.rows = rows
+	t.sortRows()
+
+	// drop the marks of removed rows
+	keys := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		keys[row.Key] = true
+	}
+	for key := range t.marked {
+		if !keys[key] {
+			delete(t.marked, key)
+		}
+	}
+
+	t.selectKey(key)
+	t.render()
+	return t
+}
+
+// SortBy sort rows by column, column -1 keeps the order rows were set
+func (t *Table) SortBy(column int, desc bool) *Table {
+	if column >= len(t.columns) {
+		return t
+	}
+
+	key := ""
+	if row, ok := t.GetSelected(); ok {
+		key = row.Key
+	}
+
+	t.sortColumn = column
+	t.sortDesc = desc
+	if t.sorter != nil {
+		t.sorter(column, desc)
+	} else {
+		t.sortRows()
+	}
+	t.selectKey(key)
+	t.render()
+	return t
+}
+
+// GetSelected get selected row, false when the table is empty
+func (t *Table) GetSelected() (TableRow, bool) {
+	if t.selected < 0 || t.selected >= len(t.rows) {
+		return TableRow{}, false
+	}
+	return t.rows[t.selected], true
+}
+
+// GetMarked get marked rows in display order
+func (t *Table) GetMarked() []TableRow {
+	var marked []TableRow
+	for _, row := range t.rows {
+		if t.marked[row.Key] {
+			marked = append(marked, row)
+		}
+	}
+	return marked
+}
+
+// ClearMarks unmark every row
+func (t *Table) ClearMarks() *Table {
+	t.marked = make(map[string]bool)
+	t.render()
+	return t
+}
+
+// GetRows get rows in display order
+func (t *Table) GetRows() []TableRow {
+	return t.rows
+}
+
+// GetLabel get table label
+func (t *Table) GetLabel() string {
+	return t.label
+}
+
+// GetPosition get table position
+func (t *Table) GetPosition() *Position {
+	return t.Position
+}
+
+// GetType get component type
+func (t *Table) GetType() ComponentType {
+	return t.ctype
+}
+
+// Focus focus to table
+func (t *Table) Focus() {
+	t.Gui.Cursor = false
+	if v, err := t.Gui.SetCurrentView(t.label); err == nil {
+		v.Highlight = len(t.rows) > 0
+	}
+}
+
+// UnFocus un focus
+func (t *Table) UnFocus() {
+	if v, err := t.Gui.View(t.label); err == nil {
+		v.Highlight = false
+	}
+}
+
+// Draw draw table
+func (t *Table) Draw() {
+	if v, err := t.Gui.SetView(t.label, t.X, t.Y, t.W, t.H, 0); err != nil {
+		if err != gocui.ErrUnknownView {
+			panic(err)
+		}
+
+		v.Title = t.label
+		v.FgColor = t.textColor
+		v.BgColor = t.textBgColor
+		v.SelFgColor = t.hilightColor
+		v.SelBgColor = t.hilightBgColor
+	}
+
+	keys := Handlers{
+		gocui.KeyArrowUp:     t.move(-1),
+		gocui.KeyArrowDown:   t.move(1),
+		gocui.KeyPgup:        t.movePage(-1),
+		gocui.KeyPgdn:        t.movePage(1),
+		gocui.KeyHome:        t.moveTo(0),
+		gocui.KeyEnd:         t.moveTo(-1),
+		gocui.MouseWheelUp:   t.move(-1),
+		gocui.MouseWheelDown: t.move(1),
+		gocui.MouseLeft:      t.click,
+		gocui.KeyArrowLeft:   t.sortNext(-1),
+		gocui.KeyArrowRight:  t.sortNext(1),
+		'r':                  t.reverse,
+	}
+
+	if t.multi {
+		keys[gocui.KeySpace] = t.toggleMark
+	}
+
+	for i := range t.columns {
+		if i >= 9 {
+			break
+		}
+		column := i
+		keys[rune('1'+i)] = func(g *gocui.Gui, v *gocui.View) error {
+			t.toggleSort(column)
+			return nil
+		}
+	}
+
+	// user handlers take precedence over the built in ones
+	for key, handler := range t.handlers {
+		keys[key] = handler
+	}
+
+	for key, handler := range keys {
+		if err := t.Gui.SetKeybinding(t.label, key, gocui.ModNone, handler); err != nil {
+			panic(err)
+		}
+	}
+
+	t.render()
+}
+
+// Close close table
+func (t *Table) Close() {
+	if err := t.DeleteView(t.label); err != nil {
+		if err != gocui.ErrUnknownView {
+			panic(err)
+		}
+	}
+
+	t.DeleteKeybindings(t.label)
+}
+
+// AddHandlerOnly add handler not return
+func (t *Table) AddHandlerOnly(key Key, handler Handler) {
+	t.AddHandler(key, handler)
+}
+
+// pageSize number of rows visible below the header
+func (t *Table) pageSize() int {
+	size := t.H - t.Y - 2
+	if size < 1 {
+		return 1
+	}
+	return size
+}
+
+// render write the header and the visible rows to the view
+func (t *Table) render() {
+	v, err := t.Gui.View(t.label)
+	if err != nil {
+		return
+	}
+
+	v.Clear()
+	v.SetOrigin(0, 0)
+
+	header := make([]string, len(t.columns))
+	for i, column := range t.columns {
+		title := column.Title
+		if i == t.sortColumn {
+			if t.sortDesc {
+				title += " ▼"
+			} else {
+				title += " ▲"
+			}
+		}
+		header[i] = pad(title, column.Width)
+	}
+	fmt.Fprintf(v, "\x1b[1m%s%s\x1b[0m\n", t.markPrefix(""), strings.Join(header, " "))
+
+	if len(t.rows) == 0 {
+		fmt.Fprintln(v, t.empty)
+		v.Highlight = false
+		return
+	}
+
+	end := t.offset + t.pageSize()
+	if end > len(t.rows) {
+		end = len(t.rows)
+	}
+	for _, row := range t.rows[t.offset:end] {
+		cells := make([]string, len(t.columns))
+		for i, column := range t.columns {
+			cell := ""
+			if i < len(row.Cells) {
+				cell = row.Cells[i]
+			}
+			cells[i] = pad(cell, column.Width)
+		}
+		fmt.Fprintln(v, t.markPrefix(row.Key)+strings.Join(cells, " "))
+	}
+
+	v.Highlight = t.Gui.CurrentView() == v
+	v.SetCursor(0, t.selected-t.offset+1)
+}
+
+// selectRow select the row at index, keeping it visible
+func (t *Table) selectRow(index int) {
+	if len(t.rows) == 0 {
+		t.selected, t.offset = 0, 0
+		return
+	}
+
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(t.rows) {
+		index = len(t.rows) - 1
+	}
+	t.selected = index
+
+	if t.selected < t.offset {
+		t.offset = t.selected
+	}
+	if t.selected >= t.offset+t.pageSize() {
+		t.offset = t.selected - t.pageSize() + 1
+	}
+}
+
+// selectKey select the row with the key or the first row when not found
+func (t *Table) selectKey(key string) {
+	for i, row := range t.rows {
+		if row.Key == key {
+			t.selectRow(i)
+			return
+		}
+	}
+	t.offset = 0
+	t.selectRow(0)
+}
+
+func (t *Table) move(delta int) Handler {
+	return func(g *gocui.Gui, v *gocui.View) error {
+		t.selectRow(t.selected + delta)
+		t.render()
+		return nil
+	}
+}
+
+func (t *Table) movePage(direction int) Handler {
+	return func(g *gocui.Gui, v *gocui.View) error {
+		t.selectRow(t.selected + direction*t.pageSize())
+		t.render()
+		return nil
+	}
+}
+
+// moveTo select the row at index, -1 is the last row
+func (t *Table) moveTo(index int) Handler {
+	return func(g *gocui.Gui, v *gocui.View) error {
+		if index < 0 {
+			index = len(t.rows) - 1
+		}
+		t.selectRow(index)
+		t.render()
+		return nil
+	}
+}
+
+// click select the clicked row or sort by the clicked header column
+func (t *Table) click(g *gocui.Gui, v *gocui.View) error {
+	t.Focus()
+
+	cx, cy := v.Cursor()
+	if cy == 0 {
+		x := len([]rune(t.markPrefix("")))
+		for i, column := range t.columns {
+			x += column.Width + 1
+			if cx < x {
+				t.toggleSort(i)
+				break
+			}
+		}
+		return nil
+	}
+
+	if index := t.offset + cy - 1; index < len(t.rows) {
+		t.selectRow(index)
+	}
+	t.render()
+	return nil
+}
+
+// toggleMark mark or unmark the selected row
+func (t *Table) toggleMark(g *gocui.Gui, v *gocui.View) error {
+	row, ok := t.GetSelected()
+	if !ok {
+		return nil
+	}
+
+	if t.marked[row.Key] {
+		delete(t.marked, row.Key)
+	} else {
+		t.marked[row.Key] = true
+	}
+	t.render()
+	return nil
+}
+
+// markPrefix mark column of the row with the key, empty without multi select
+func (t *Table) markPrefix(key string) string {
+	switch {
+	case !t.multi:
+		return ""
+	case t.marked[key]:
+		return "* "
+	default:
+		return "  "
+	}
+}
+
+// sortNext sort by the previous or next column
+func (t *Table) sortNext(delta int) Handler {
+	return func(g *gocui.Gui, v *gocui.View) error {
+		if len(t.columns) == 0 {
+			return nil
+		}
+		column := (t.sortColumn + delta + len(t.columns)) % len(t.columns)
+		if t.sortColumn < 0 && delta < 0 {
+			column = len(t.columns) - 1
+		}
+		t.SortBy(column, false)
+		return nil
+	}
+}
+
+// reverse reverse the sort direction
+func (t *Table) reverse(g *gocui.Gui, v *gocui.View) error {
+	if t.sortColumn >= 0 {
+		t.SortBy(t.sortColumn, !t.sortDesc)
+	}
+	return nil
+}
+
+// toggleSort sort by column, reversing the direction when already sorted by it
+func (t *Table) toggleSort(column int) {
+	t.SortBy(column, column == t.sortColumn && !t.sortDesc)
+}
+
+func (t *Table) sortRows() {
+	if t.sortColumn < 0 || t.sorter != nil {
+		return
+	}
+
+	column := t.sortColumn
+	sort.SliceStable(t.rows, func(i, j int) bool {
+		a, b := cell(t.rows[i], column), cell(t.rows[j], column)
+		// cells without a value stay at the end in both directions
+		if emptyA, emptyB := isEmptyCell(a), isEmptyCell(b); emptyA || emptyB {
+			return !emptyA
+		}
+		if t.sortDesc {
+			return compareCells(b, a) < 0
+		}
+		return compareCells(a, b) < 0
+	})
+}
+
+func cell(row TableRow, column int) string {
+	if column < len(row.Cells) {
+		return row.Cells[column]
+	}
+	return ""
+}
+
+// isEmptyCell reports whether the cell is blank or the "-" placeholder
+func isEmptyCell(text string) bool {
+	text = strings.TrimSpace(text)
+	return text == "" || text == "-"
+}
+
+// compareCells compare numbers by value and texts case insensitive, numbers
+// come before texts
+func compareCells(a, b string) int {
+	na, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	nb, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+
+	switch {
+	case errA == nil && errB == nil:
+		switch {
+		case na < nb:
+			return -1
+		case na > nb:
+			return 1
+		}
+		return 0
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// pad pad or cut text to width runes
+func pad(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) > width {
+		if width > 1 {
+			return string(runes[:width-1]) + "…"
+		}
+		return string(runes[:width])
+	}
+	return text + strings.Repeat(" ", width-len(runes))
+}
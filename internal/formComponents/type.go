@@ -53,4 +53,6 @@ const (
 	TypeRadio
 	// TypeTable type is table component
 	TypeTable
+	// TypeModal type is modal dialog component
+	TypeModal
 )
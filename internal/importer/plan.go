@@ -0,0 +1,222 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	"github.com/WellyngtonF/WishListCLI/internal/repository"
+)
+
+// Policy decides what happens to a record matching an existing item, by ID
+// or by name ignoring case, or an item created earlier by the same import
+type Policy string
+
+// Policies accepted by ParsePolicy
+const (
+	// PolicySkip keeps the existing item
+	PolicySkip Policy = "skip"
+	// PolicyUpdate sets the fields of the record on the existing item
+	PolicyUpdate Policy = "update"
+	// PolicyRename creates a new item named like "PS5 (2)"
+	PolicyRename Policy = "rename"
+	// PolicyError stops the import before anything is changed
+	PolicyError Policy = "error"
+)
+
+// ParsePolicy returns the policy with the given name
+func ParsePolicy(name string) (Policy, error) {
+	switch policy := Policy(strings.ToLower(strings.TrimSpace(name))); policy {
+	case PolicySkip, PolicyUpdate, PolicyRename, PolicyError:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown conflict policy %q, expected skip, update, rename or error", name)
+}
+
+// ActionKind is what the import does with a record
+type ActionKind string
+
+// Action kinds
+const (
+	ActionCreate ActionKind = "create"
+	ActionUpdate ActionKind = "update"
+	ActionSkip   ActionKind = "skip"
+)
+
+// Action is the change planned for a record
+type Action struct {
+	Kind   ActionKind
+	Record Record
+	// Item is the item to create or the updated item, the existing one when skipped
+	Item item.Item
+	// Detail explains the action: the changed fields, the new name or why
+	// the record is skipped
+	Detail string
+}
+
+// Plan lists the action of every record in file order
+type Plan struct {
+	Actions []Action
+}
+
+// Count returns the number of actions of the kind
+func (p *Plan) Count(kind ActionKind) int {
+	n := 0
+	for _, action := range p.Actions {
+		if action.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// NewPlan plans the import of the records into a wishlist holding existing
+func NewPlan(existing []item.Item, records []Record, policy Policy) (*Plan, error) {
+	// known holds the items as they will be once the previous actions are applied
+	known := append([]item.Item(nil), existing...)
+	lines := make(map[string]int)
+
+	plan := &Plan{}
+	for _, record := range records {
+		action, err := planRecord(known, record, policy)
+		if err != nil {
+			if line := lines[action.Item.ID]; line > 0 {
+				err = fmt.Errorf("%v, imported at line %d", err, line)
+			}
+			return nil, lineError(record, err)
+		}
+
+		switch action.Kind {
+		case ActionCreate:
+			known = append(known, action.Item)
+			lines[action.Item.ID] = record.Line
+		case ActionUpdate:
+			for i := range known {
+				if known[i].ID == action.Item.ID {
+					known[i] = action.Item
+				}
+			}
+		}
+		plan.Actions = append(plan.Actions, action)
+	}
+	return plan, nil
+}
+
+// planRecord returns the action of a record given the known items
+func planRecord(known []item.Item, record Record, policy Policy) (Action, error) {
+	match := findMatch(known, record)
+	if match == nil {
+		if strings.TrimSpace(record.Item.Name) == "" {
+			return Action{Kind: ActionSkip, Record: record, Detail: "missing name"}, nil
+		}
+
+		created := record.Item
+		if created.ID == "" {
+			created.ID = item.NewID()
+		}
+		return Action{Kind: ActionCreate, Record: record, Item: created}, nil
+	}
+
+	switch policy {
+	case PolicyUpdate:
+		updated := record.merge(*match)
+		// only records matched by ID rename, a name matched ignoring case is kept
+		if match.ID != record.Item.ID {
+			updated.Name = match.Name
+		}
+		if other := findName(known, updated.Name); other != nil && other.ID != match.ID {
+			return Action{Item: *match}, fmt.Errorf("cannot rename %q to %q, the name is in use", match.Name, updated.Name)
+		}
+
+		diff := changes(*match, updated)
+		if len(diff) == 0 {
+			return Action{Kind: ActionSkip, Record: record, Item: *match, Detail: "unchanged"}, nil
+		}
+		return Action{Kind: ActionUpdate, Record: record, Item: updated, Detail: strings.Join(diff, ", ")}, nil
+
+	case PolicyRename:
+		if strings.TrimSpace(record.Item.Name) == "" {
+			return Action{Kind: ActionSkip, Record: record, Item: *match, Detail: "missing name"}, nil
+		}
+
+		created := record.Item
+		created.ID = item.NewID()
+		created.Name = freeName(known, record.Item.Name)
+		return Action{Kind: ActionCreate, Record: record, Item: created, Detail: fmt.Sprintf("renamed from %q", record.Item.Name)}, nil
+
+	case PolicyError:
+		return Action{Item: *match}, fmt.Errorf("%q already exists", match.Name)
+
+	default:
+		return Action{Kind: ActionSkip, Record: record, Item: *match, Detail: fmt.Sprintf("%q already exists", match.Name)}, nil
+	}
+}
+
+// findMatch returns the item with the record ID, or with its name when no
+// item has that ID
+func findMatch(known []item.Item, record Record) *item.Item {
+	if record.Item.ID != "" {
+		for i := range known {
+			if known[i].ID == record.Item.ID {
+				return &known[i]
+			}
+		}
+	}
+	return findName(known, record.Item.Name)
+}
+
+// findName returns the item with the name, ignoring case and surrounding spaces
+func findName(known []item.Item, name string) *item.Item {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil
+	}
+
+	for i := range known {
+		if strings.EqualFold(strings.TrimSpace(known[i].Name), name) {
+			return &known[i]
+		}
+	}
+	return nil
+}
+
+// freeName returns name followed by the first free number, like "PS5 (2)"
+func freeName(known []item.Item, name string) string {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s (%d)", strings.TrimSpace(name), n)
+		if findName(known, candidate) == nil {
+			return candidate
+		}
+	}
+}
+
+func lineError(record Record, err error) error {
+	if record.Line > 0 {
+		return fmt.Errorf("line %d: %v", record.Line, err)
+	}
+	return fmt.Errorf("%s: %v", record.Item.Name, err)
+}
+
+// Apply runs the plan on the repository after a single backup, stopping at
+// the first error. Plans only skipping records change nothing.
+func Apply(repo *repository.Repository, plan *Plan) error {
+	if plan.Count(ActionSkip) == len(plan.Actions) {
+		return nil
+	}
+
+	return repo.WithBackup("import", func() error {
+		for _, action := range plan.Actions {
+			var err error
+			switch action.Kind {
+			case ActionCreate:
+				err = repo.CreateItem(action.Item)
+			case ActionUpdate:
+				err = repo.UpdateItem(action.Item)
+			}
+			if err != nil {
+				return lineError(action.Record, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,130 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+)
+
+func TestNewPlan(t *testing.T) {
+	existing := []item.Item{
+		{ID: "ps5", Name: "PS5", MaxPrice: 3500},
+		{ID: "xbox", Name: "Xbox", MaxPrice: 3000},
+		{ID: "ps5-2", Name: "PS5 (2)", MaxPrice: 3400},
+	}
+
+	record := func(line int, name string, maxPrice string) Record {
+		r := newRecord(line)
+		r.set(FieldName, name, false, ",")
+		r.set(FieldMaxPrice, maxPrice, false, ",")
+		return r
+	}
+	withID := func(r Record, id string) Record {
+		r.set(FieldID, id, false, ",")
+		return r
+	}
+
+	type want struct {
+		kind   ActionKind
+		name   string
+		detail string
+	}
+	tests := []struct {
+		name    string
+		policy  Policy
+		records []Record
+		want    []want
+		wantErr string
+	}{
+		{
+			name:    "skip",
+			policy:  PolicySkip,
+			records: []Record{record(2, "ps5", "3200"), record(3, "Switch", "2000")},
+			want:    []want{{ActionSkip, "PS5", `"PS5" already exists`}, {ActionCreate, "Switch", ""}},
+		},
+		{
+			name:   "update keeps the name of a name match",
+			policy: PolicyUpdate,
+			records: []Record{
+				record(2, "ps5", "3200"),
+				record(3, "Xbox", "3000"),
+				withID(record(4, "Xbox Series X", "2900"), "xbox"),
+			},
+			want: []want{
+				{ActionUpdate, "PS5", `max_price "3500" -> "3200"`},
+				{ActionSkip, "Xbox", "unchanged"},
+				{ActionUpdate, "Xbox Series X", `name "Xbox" -> "Xbox Series X", max_price "3000" -> "2900"`},
+			},
+		},
+		{
+			name:    "update cannot rename to a used name",
+			policy:  PolicyUpdate,
+			records: []Record{withID(record(5, "ps5", "3000"), "xbox")},
+			wantErr: `line 5: cannot rename "Xbox" to "ps5", the name is in use`,
+		},
+		{
+			name:    "rename",
+			policy:  PolicyRename,
+			records: []Record{record(2, "PS5", "3200"), record(3, "PS5", "3100")},
+			want:    []want{{ActionCreate, "PS5 (3)", `renamed from "PS5"`}, {ActionCreate, "PS5 (4)", `renamed from "PS5"`}},
+		},
+		{
+			name:    "error",
+			policy:  PolicyError,
+			records: []Record{record(2, "Switch", "2000"), record(3, "Xbox", "2900")},
+			wantErr: `line 3: "Xbox" already exists`,
+		},
+		{
+			name:    "error on a record of the same file",
+			policy:  PolicyError,
+			records: []Record{record(2, "Switch", "2000"), record(3, "switch", "1900")},
+			wantErr: `line 3: "Switch" already exists, imported at line 2`,
+		},
+		{
+			name:    "missing name",
+			policy:  PolicyUpdate,
+			records: []Record{record(2, "", "2000")},
+			want:    []want{{ActionSkip, "", "missing name"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := NewPlan(existing, tt.records, tt.policy)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("NewPlan error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewPlan error: %v", err)
+			}
+
+			if len(plan.Actions) != len(tt.want) {
+				t.Fatalf("actions = %d, want %d", len(plan.Actions), len(tt.want))
+			}
+			for i, action := range plan.Actions {
+				got := want{action.Kind, action.Item.Name, action.Detail}
+				if got != tt.want[i] {
+					t.Errorf("action %d = %+v, want %+v", i, got, tt.want[i])
+				}
+				if action.Kind == ActionCreate && strings.TrimSpace(action.Item.ID) == "" {
+					t.Errorf("action %d creates an item without ID", i)
+				}
+			}
+		})
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	for _, name := range []string{"skip", "Update", " rename ", "ERROR"} {
+		if _, err := ParsePolicy(name); err != nil {
+			t.Errorf("ParsePolicy(%q) error: %v", name, err)
+		}
+	}
+	if _, err := ParsePolicy("overwrite"); err == nil {
+		t.Error(`ParsePolicy("overwrite") expected an error`)
+	}
+}
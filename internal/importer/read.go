@@ -0,0 +1,326 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/WellyngtonF/WishListCLI/internal/persistence"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// Format of an import file
+type Format string
+
+// Formats accepted by ParseFormat
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// ParseFormat returns the format with the given name, yml is accepted for yaml
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "csv":
+		return FormatCSV, nil
+	case "json":
+		return FormatJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	}
+	return "", fmt.Errorf("unknown import format %q, expected csv, json or yaml", name)
+}
+
+// DetectFormat returns the format matching the file extension, CSV when unknown
+func DetectFormat(filePath string) Format {
+	if format, err := ParseFormat(strings.TrimPrefix(filepath.Ext(filePath), ".")); err == nil {
+		return format
+	}
+	return FormatCSV
+}
+
+// Mapping describes the layout of a CSV file
+type Mapping struct {
+	// Delimiter separates the columns, it is detected from the header when zero
+	Delimiter rune
+	// DecimalComma reads 1.234,56 as 1234.56
+	DecimalComma bool
+	// ListSeparator separates the values of sources and keywords, "," by default
+	ListSeparator string
+	// Columns maps header names to fields, ignoring case and punctuation.
+	// Headers not listed are matched to the field or alias with their name.
+	Columns map[string]Field
+}
+
+// MappingFromViper reads the import.csv section of the config
+func MappingFromViper() (Mapping, error) {
+	m := Mapping{
+		DecimalComma:  viper.GetBool("import.csv.decimal_comma"),
+		ListSeparator: viper.GetString("import.csv.list_separator"),
+	}
+
+	if delimiter := viper.GetString("import.csv.delimiter"); delimiter != "" {
+		if err := m.SetDelimiter(delimiter); err != nil {
+			return m, fmt.Errorf("invalid import.csv.delimiter: %v", err)
+		}
+	}
+
+	for header, name := range viper.GetStringMapString("import.csv.columns") {
+		if err := m.MapColumn(header, name); err != nil {
+			return m, fmt.Errorf("invalid import.csv.columns: %v", err)
+		}
+	}
+	return m, nil
+}
+
+// SetDelimiter sets the delimiter from a single character, "tab" or "\t"
+func (m *Mapping) SetDelimiter(delimiter string) error {
+	switch delimiter {
+	case "tab", `\t`:
+		delimiter = "\t"
+	}
+
+	runes := []rune(delimiter)
+	if len(runes) != 1 {
+		return fmt.Errorf("expected a single character, got %q", delimiter)
+	}
+	m.Delimiter = runes[0]
+	return nil
+}
+
+// MapColumn maps the header to the field named name, "-" ignores the column
+func (m *Mapping) MapColumn(header, name string) error {
+	if m.Columns == nil {
+		m.Columns = make(map[string]Field)
+	}
+
+	if name = strings.TrimSpace(name); name == "-" || name == "" {
+		m.Columns[normalize(header)] = ""
+		return nil
+	}
+
+	field, ok := ParseField(name)
+	if !ok {
+		return fmt.Errorf("unknown field %q for column %q", name, header)
+	}
+	m.Columns[normalize(header)] = field
+	return nil
+}
+
+// ParseColumns maps the columns listed like "Produto=name,Preço=max_price"
+func (m *Mapping) ParseColumns(list string) error {
+	for _, pair := range strings.Split(list, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		header, name, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("expected HEADER=FIELD, got %q", pair)
+		}
+		if err := m.MapColumn(header, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// column returns the field of a header and whether it is imported
+func (m Mapping) column(header string) (Field, bool) {
+	if field, ok := m.Columns[normalize(header)]; ok {
+		return field, field != ""
+	}
+	return ParseField(header)
+}
+
+// File is the content of an import file
+type File struct {
+	Records []Record
+	// Ignored lists the CSV columns not mapped to any field
+	Ignored []string
+}
+
+// Read reads the records of a file in the given format
+func Read(r io.Reader, format Format, mapping Mapping) (*File, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatJSON:
+		var values any
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, err
+		}
+		return decodeValues(values)
+	case FormatYAML:
+		var values any
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			return nil, err
+		}
+		return decodeValues(values)
+	default:
+		return readCSV(data, mapping)
+	}
+}
+
+// decodeValues reads the items of a JSON or YAML document, either a list or
+// an object with an items list, keyed by field names or aliases
+func decodeValues(values any) (*File, error) {
+	if object, ok := values.(map[string]any); ok {
+		values = object["items"]
+	}
+
+	list, ok := values.([]any)
+	if !ok {
+		return nil, errors.New("expected a list of items or an object with an items list")
+	}
+
+	file := &File{}
+	for i, value := range list {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("item %d: expected an object", i+1)
+		}
+
+		record := newRecord(0)
+		for key, value := range object {
+			field, ok := ParseField(key)
+			if !ok {
+				continue
+			}
+			if err := record.setValue(field, value); err != nil {
+				return nil, fmt.Errorf("item %d: %v", i+1, err)
+			}
+		}
+		file.Records = append(file.Records, record)
+	}
+	return file, nil
+}
+
+// readCSV reads a CSV file with a header row. Files written by the wishlist,
+// including the old ones without a header, are read as they are stored
+// unless columns are mapped.
+func readCSV(data []byte, mapping Mapping) (*File, error) {
+	if len(mapping.Columns) == 0 && bytes.HasPrefix(data, []byte("#schema:")) {
+		return readWishlistCSV(data)
+	}
+
+	if mapping.ListSeparator == "" {
+		mapping.ListSeparator = ","
+	}
+	if mapping.Delimiter == 0 {
+		mapping.Delimiter = detectDelimiter(data)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = mapping.Delimiter
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return &File{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	file := &File{}
+	fields := make([]Field, len(header))
+	hasName := false
+	for i, name := range header {
+		field, ok := mapping.column(name)
+		if !ok {
+			file.Ignored = append(file.Ignored, name)
+			continue
+		}
+		fields[i] = field
+		hasName = hasName || field == FieldName
+	}
+
+	// without a name column it may be an old wishlist file without a header,
+	// which is always separated by semicolons
+	if !hasName {
+		if len(mapping.Columns) == 0 && mapping.Delimiter == ';' {
+			if file, err := readWishlistCSV(data); err == nil {
+				return file, nil
+			}
+		}
+		return nil, errors.New("no column is mapped to name")
+	}
+
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		record := newRecord(line)
+		for i, value := range row {
+			if i >= len(fields) || fields[i] == "" {
+				continue
+			}
+			if err := record.set(fields[i], value, mapping.DecimalComma, mapping.ListSeparator); err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+		}
+		file.Records = append(file.Records, record)
+	}
+	return file, nil
+}
+
+// readWishlistCSV reads a file in the wishlist storage format, every field
+// is set
+func readWishlistCSV(data []byte) (*File, error) {
+	items, err := persistence.ReadItemsCSV(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	file := &File{}
+	for _, itm := range items {
+		record := newRecord(0)
+		record.Item = itm
+		for _, field := range Fields {
+			if field != FieldID || itm.ID != "" {
+				record.Fields[field] = true
+			}
+		}
+		file.Records = append(file.Records, record)
+	}
+	return file, nil
+}
+
+// detectDelimiter returns the most frequent of comma, semicolon and tab in
+// the first line that is not a comment
+func detectDelimiter(data []byte) rune {
+	var header string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			header = line
+			break
+		}
+	}
+
+	delimiter, best := ',', 0
+	for _, candidate := range []rune{',', ';', '\t'} {
+		if n := strings.Count(header, string(candidate)); n > best {
+			delimiter, best = candidate, n
+		}
+	}
+	return delimiter
+}
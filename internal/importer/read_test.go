@@ -0,0 +1,225 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetectDelimiter(t *testing.T) {
+	tests := []struct {
+		data string
+		want rune
+	}{
+		{"name,category,max_price\nPS5,Games,3500\n", ','},
+		{"name;category;max_price\nPS5;Games;3.500,00\n", ';'},
+		{"name\tcategory\tmax_price\n", '\t'},
+		{"# exported from a spreadsheet\n\nname;max_price\n", ';'},
+		{"name;description\n", ';'},
+		{"name\n", ','},
+		{"", ','},
+	}
+
+	for _, tt := range tests {
+		if got := detectDelimiter([]byte(tt.data)); got != tt.want {
+			t.Errorf("detectDelimiter(%q) = %q, want %q", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		mapping     Mapping
+		columns     string
+		wantNames   []string
+		wantMax     []float64
+		wantSources [][]string
+		wantIgnored []string
+	}{
+		{
+			name:        "aliases and punctuation in headers",
+			data:        "Item Name,Max-Price,Brand,Notes\nPS5,3500.50,Sony,gift\n\"Xbox Series X\",\"4,199.90\",Microsoft,\n",
+			wantNames:   []string{"PS5", "Xbox Series X"},
+			wantMax:     []float64{3500.5, 4199.9},
+			wantSources: [][]string{nil, nil},
+			wantIgnored: []string{"Notes"},
+		},
+		{
+			name:        "decimal comma and list separator",
+			data:        "product;price;stores\nPS5;R$ 3.500,00;amazon|MERCADO LIVRE\n",
+			mapping:     Mapping{DecimalComma: true, ListSeparator: "|"},
+			wantNames:   []string{"PS5"},
+			wantMax:     []float64{3500},
+			wantSources: [][]string{{"Amazon", "Mercado Livre"}},
+		},
+		{
+			name:        "mapped columns",
+			data:        "Produto;Preço;Loja;Marca\nPS5;3500;Kabum;Sony\n",
+			columns:     "Produto=name,Preço=max_price,Loja=-",
+			wantNames:   []string{"PS5"},
+			wantMax:     []float64{3500},
+			wantSources: [][]string{nil},
+			wantIgnored: []string{"Loja", "Marca"},
+		},
+		{
+			name:        "empty file",
+			data:        "",
+			wantNames:   nil,
+			wantMax:     nil,
+			wantSources: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping := tt.mapping
+			if err := mapping.ParseColumns(tt.columns); err != nil {
+				t.Fatal(err)
+			}
+
+			file, err := readCSV([]byte(tt.data), mapping)
+			if err != nil {
+				t.Fatalf("readCSV error: %v", err)
+			}
+
+			var names []string
+			var maxPrices []float64
+			var sourceLists [][]string
+			for _, record := range file.Records {
+				names = append(names, record.Item.Name)
+				maxPrices = append(maxPrices, record.Item.MaxPrice)
+				sourceLists = append(sourceLists, record.Item.ScrapingSources)
+			}
+
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("names = %q, want %q", names, tt.wantNames)
+			}
+			if !reflect.DeepEqual(maxPrices, tt.wantMax) {
+				t.Errorf("max prices = %v, want %v", maxPrices, tt.wantMax)
+			}
+			if !reflect.DeepEqual(sourceLists, tt.wantSources) {
+				t.Errorf("sources = %q, want %q", sourceLists, tt.wantSources)
+			}
+			if !reflect.DeepEqual(file.Ignored, tt.wantIgnored) {
+				t.Errorf("ignored = %q, want %q", file.Ignored, tt.wantIgnored)
+			}
+		})
+	}
+}
+
+func TestReadCSVWishlistFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"current format", "#schema:4\nID;Name;MaxPrice;ScrapingSources\nabc;PS5;3500.00;Amazon\n"},
+		{"legacy without header", "PS5;Games;Sony;3500.00;Amazon;2024-01-01T00:00:00Z;2024-01-01T00:00:00Z;0.00\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := readCSV([]byte(tt.data), Mapping{})
+			if err != nil {
+				t.Fatalf("readCSV error: %v", err)
+			}
+			if len(file.Records) != 1 {
+				t.Fatalf("records = %d, want 1", len(file.Records))
+			}
+
+			record := file.Records[0]
+			if record.Item.Name != "PS5" || record.Item.MaxPrice != 3500 {
+				t.Errorf("item = %+v", record.Item)
+			}
+			if !record.Fields[FieldCategory] || !record.Fields[FieldSourceURLs] {
+				t.Errorf("wishlist records must set every field, got %v", record.Fields)
+			}
+		})
+	}
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		columns string
+	}{
+		{"no name column", "price,brand\n3500,Sony\n", ""},
+		{"name column ignored", "name,price\nPS5,3500\n", "name=-"},
+		{"invalid price", "name,price\nPS5,cheap\n", ""},
+		{"negative price", "name,price\nPS5,-10\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mapping Mapping
+			if err := mapping.ParseColumns(tt.columns); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := readCSV([]byte(tt.data), mapping); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestReadDocuments(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		data   string
+	}{
+		{"json list", FormatJSON, `[{"name": "PS5", "price": 3500, "stores": ["amazon"], "source_urls": {"amazon": "https://www.amazon.com.br/dp/B0"}}]`},
+		{"json items object", FormatJSON, `{"items": [{"Item Name": "PS5", "max_price": "3500", "sources": "Amazon"}]}`},
+		{"yaml list", FormatYAML, "- name: PS5\n  max_price: 3500\n  sources: [amazon]\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := Read(strings.NewReader(tt.data), tt.format, Mapping{})
+			if err != nil {
+				t.Fatalf("Read error: %v", err)
+			}
+			if len(file.Records) != 1 {
+				t.Fatalf("records = %d, want 1", len(file.Records))
+			}
+
+			itm := file.Records[0].Item
+			if itm.Name != "PS5" || itm.MaxPrice != 3500 || !reflect.DeepEqual(itm.ScrapingSources, []string{"Amazon"}) {
+				t.Errorf("item = %+v", itm)
+			}
+		})
+	}
+}
+
+func TestMappingParseColumns(t *testing.T) {
+	var m Mapping
+	if err := m.ParseColumns("Produto=name, Preço Máximo=max_price,Obs=-"); err != nil {
+		t.Fatalf("ParseColumns error: %v", err)
+	}
+
+	tests := []struct {
+		header    string
+		wantField Field
+		wantOK    bool
+	}{
+		{"PRODUTO", FieldName, true},
+		{"preço máximo", FieldMaxPrice, true},
+		{"Obs", "", false},
+		{"Brand", FieldProducer, true},
+		{"Unknown", "", false},
+	}
+	for _, tt := range tests {
+		field, ok := m.column(tt.header)
+		if field != tt.wantField || ok != tt.wantOK {
+			t.Errorf("column(%q) = %q, %v, want %q, %v", tt.header, field, ok, tt.wantField, tt.wantOK)
+		}
+	}
+
+	for _, list := range []string{"Produto", "Produto=title"} {
+		if err := m.ParseColumns(list); err == nil {
+			t.Errorf("ParseColumns(%q) expected an error", list)
+		}
+	}
+}
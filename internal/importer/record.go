@@ -0,0 +1,310 @@
+package importer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	"github.com/WellyngtonF/WishListCLI/internal/persistence"
+	"github.com/WellyngtonF/WishListCLI/internal/scraper/sources"
+)
+
+// Field is an item field that can be imported, named like the JSON output
+type Field string
+
+// Fields accepted by ParseField
+const (
+	FieldID               Field = "id"
+	FieldName             Field = "name"
+	FieldCategory         Field = "category"
+	FieldProducer         Field = "producer"
+	FieldMaxPrice         Field = "max_price"
+	FieldMinPrice         Field = "min_price"
+	FieldSources          Field = "sources"
+	FieldURL              Field = "url"
+	FieldRequiredKeywords Field = "required_keywords"
+	FieldExcludedKeywords Field = "excluded_keywords"
+	FieldSourceURLs       Field = "source_urls"
+)
+
+// Fields lists every importable field
+var Fields = []Field{
+	FieldID,
+	FieldName,
+	FieldCategory,
+	FieldProducer,
+	FieldMaxPrice,
+	FieldMinPrice,
+	FieldSources,
+	FieldURL,
+	FieldRequiredKeywords,
+	FieldExcludedKeywords,
+	FieldSourceURLs,
+}
+
+// fieldAliases maps normalized column and key names to fields, besides the
+// field names themselves
+var fieldAliases = map[string]Field{
+	"itemid":          FieldID,
+	"item":            FieldName,
+	"itemname":        FieldName,
+	"product":         FieldName,
+	"brand":           FieldProducer,
+	"manufacturer":    FieldProducer,
+	"price":           FieldMaxPrice,
+	"source":          FieldSources,
+	"scrapingsources": FieldSources,
+	"stores":          FieldSources,
+	"link":            FieldURL,
+	"required":        FieldRequiredKeywords,
+	"excluded":        FieldExcludedKeywords,
+	"productpages":    FieldSourceURLs,
+}
+
+// ParseField returns the field named name or one of its aliases, ignoring
+// case, spaces, dashes and underscores
+func ParseField(name string) (Field, bool) {
+	key := normalize(name)
+	for _, field := range Fields {
+		if normalize(string(field)) == key {
+			return field, true
+		}
+	}
+	field, ok := fieldAliases[key]
+	return field, ok
+}
+
+// normalize lowercases name keeping only letters and digits
+func normalize(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// Record is an item read from an import file with the fields the file sets,
+// the other ones are left unchanged when an existing item is updated
+type Record struct {
+	// Line is where the record starts in the file, 0 when unknown
+	Line   int
+	Item   item.Item
+	Fields map[Field]bool
+}
+
+func newRecord(line int) Record {
+	return Record{Line: line, Fields: make(map[Field]bool)}
+}
+
+// set sets the field from a text value, empty values are ignored
+func (r *Record) set(field Field, value string, decimalComma bool, listSeparator string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	switch field {
+	case FieldMaxPrice, FieldMinPrice:
+		price, err := parsePrice(value, decimalComma)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", field, value)
+		}
+		return r.setPrice(field, price)
+	case FieldSources, FieldRequiredKeywords, FieldExcludedKeywords:
+		return r.setList(field, strings.Split(value, listSeparator))
+	case FieldSourceURLs:
+		r.setSourceURLs(persistence.ParseSourceURLs(value))
+		return nil
+	case FieldID:
+		r.Item.ID = value
+	case FieldName:
+		r.Item.Name = value
+	case FieldCategory:
+		r.Item.Category = value
+	case FieldProducer:
+		r.Item.Producer = value
+	case FieldURL:
+		r.Item.URL = value
+	}
+	r.Fields[field] = true
+	return nil
+}
+
+// setValue sets the field from a value decoded from JSON or YAML
+func (r *Record) setValue(field Field, value any) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return r.set(field, v, false, ",")
+	case float64:
+		return r.setNumber(field, v)
+	case int:
+		return r.setNumber(field, float64(v))
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, element := range v {
+			if element != nil {
+				values = append(values, fmt.Sprint(element))
+			}
+		}
+		return r.setList(field, values)
+	case map[string]any:
+		if field != FieldSourceURLs {
+			return fmt.Errorf("%s is not an object", field)
+		}
+		urls := make(map[string]string, len(v))
+		for source, url := range v {
+			if url, ok := url.(string); ok {
+				urls[source] = url
+			}
+		}
+		r.setSourceURLs(urls)
+		return nil
+	default:
+		return r.set(field, fmt.Sprint(v), false, ",")
+	}
+}
+
+func (r *Record) setNumber(field Field, value float64) error {
+	switch field {
+	case FieldMaxPrice, FieldMinPrice:
+		return r.setPrice(field, value)
+	}
+	return r.set(field, strconv.FormatFloat(value, 'f', -1, 64), false, ",")
+}
+
+func (r *Record) setPrice(field Field, price float64) error {
+	if price < 0 {
+		return fmt.Errorf("invalid %s %v, prices cannot be negative", field, price)
+	}
+
+	switch field {
+	case FieldMaxPrice:
+		r.Item.MaxPrice = price
+	case FieldMinPrice:
+		r.Item.MinPrice = price
+	default:
+		return fmt.Errorf("%s is not a price", field)
+	}
+	r.Fields[field] = true
+	return nil
+}
+
+// setList sets a list field, known sources get their canonical names
+func (r *Record) setList(field Field, values []string) error {
+	var list []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		if source, ok := sources.Lookup(value); ok && field == FieldSources {
+			value = source.Name()
+		}
+		list = append(list, value)
+	}
+
+	switch field {
+	case FieldSources:
+		r.Item.ScrapingSources = list
+	case FieldRequiredKeywords:
+		r.Item.RequiredKeywords = list
+	case FieldExcludedKeywords:
+		r.Item.ExcludedKeywords = list
+	default:
+		return fmt.Errorf("%s is not a list", field)
+	}
+	r.Fields[field] = true
+	return nil
+}
+
+// setSourceURLs sets the product pages, known sources get their canonical names
+func (r *Record) setSourceURLs(urls map[string]string) {
+	for source, url := range sources.CanonicalURLs(urls) {
+		if r.Item.SourceURLs == nil {
+			r.Item.SourceURLs = make(map[string]string)
+		}
+		r.Item.SourceURLs[source] = url
+	}
+	r.Fields[FieldSourceURLs] = true
+}
+
+// parsePrice parses a price ignoring currency symbols and spaces. With a
+// decimal comma dots separate thousands, otherwise commas do.
+func parsePrice(value string, decimalComma bool) (float64, error) {
+	value = strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) || r == '.' || r == ',' || r == '-' {
+			return r
+		}
+		return -1
+	}, value)
+
+	if decimalComma {
+		value = strings.ReplaceAll(value, ".", "")
+		value = strings.ReplaceAll(value, ",", ".")
+	} else {
+		value = strings.ReplaceAll(value, ",", "")
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// merge returns the existing item with the fields set by the record
+func (r Record) merge(existing item.Item) item.Item {
+	merged := existing
+	for field := range r.Fields {
+		switch field {
+		case FieldName:
+			merged.Name = r.Item.Name
+		case FieldCategory:
+			merged.Category = r.Item.Category
+		case FieldProducer:
+			merged.Producer = r.Item.Producer
+		case FieldMaxPrice:
+			merged.MaxPrice = r.Item.MaxPrice
+		case FieldMinPrice:
+			merged.MinPrice = r.Item.MinPrice
+		case FieldSources:
+			merged.ScrapingSources = r.Item.ScrapingSources
+		case FieldURL:
+			merged.URL = r.Item.URL
+		case FieldRequiredKeywords:
+			merged.RequiredKeywords = r.Item.RequiredKeywords
+		case FieldExcludedKeywords:
+			merged.ExcludedKeywords = r.Item.ExcludedKeywords
+		case FieldSourceURLs:
+			merged.SourceURLs = r.Item.SourceURLs
+		}
+	}
+	return merged
+}
+
+// changes describes the fields that differ between two items, like
+// "max_price 3500 -> 3200"
+func changes(before, after item.Item) []string {
+	values := func(itm item.Item) map[Field]string {
+		return map[Field]string{
+			FieldName:             itm.Name,
+			FieldCategory:         itm.Category,
+			FieldProducer:         itm.Producer,
+			FieldMaxPrice:         strconv.FormatFloat(itm.MaxPrice, 'f', -1, 64),
+			FieldMinPrice:         strconv.FormatFloat(itm.MinPrice, 'f', -1, 64),
+			FieldSources:          strings.Join(itm.ScrapingSources, ","),
+			FieldURL:              itm.URL,
+			FieldRequiredKeywords: strings.Join(itm.RequiredKeywords, ","),
+			FieldExcludedKeywords: strings.Join(itm.ExcludedKeywords, ","),
+			FieldSourceURLs:       persistence.FormatSourceURLs(itm.SourceURLs),
+		}
+	}
+
+	old, updated := values(before), values(after)
+	var diff []string
+	for _, field := range Fields {
+		if field != FieldID && old[field] != updated[field] {
+			diff = append(diff, fmt.Sprintf("%s %q -> %q", field, old[field], updated[field]))
+		}
+	}
+	return diff
+}
@@ -0,0 +1,108 @@
+package importer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+)
+
+func TestParseField(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   Field
+		wantOK bool
+	}{
+		{"name", FieldName, true},
+		{"Max Price", FieldMaxPrice, true},
+		{"max-price", FieldMaxPrice, true},
+		{"MaxPrice", FieldMaxPrice, true},
+		{"Item Name", FieldName, true},
+		{"product", FieldName, true},
+		{"Price", FieldMaxPrice, true},
+		{"Brand", FieldProducer, true},
+		{"ScrapingSources", FieldSources, true},
+		{"stores", FieldSources, true},
+		{"Link", FieldURL, true},
+		{"product pages", FieldSourceURLs, true},
+		{"Item ID", FieldID, true},
+		{"notes", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseField(tt.name)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ParseField(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		value        string
+		decimalComma bool
+		want         float64
+	}{
+		{"3500", false, 3500},
+		{"3,500.90", false, 3500.9},
+		{"$ 1,234,567.5", false, 1234567.5},
+		{"3.500,90", true, 3500.9},
+		{"R$ 1.234.567,5", true, 1234567.5},
+		{"99,9", true, 99.9},
+		{"-10", false, -10},
+	}
+
+	for _, tt := range tests {
+		got, err := parsePrice(tt.value, tt.decimalComma)
+		if err != nil {
+			t.Errorf("parsePrice(%q, %v) error: %v", tt.value, tt.decimalComma, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parsePrice(%q, %v) = %v, want %v", tt.value, tt.decimalComma, got, tt.want)
+		}
+	}
+
+	for _, value := range []string{"", "free", "1.2.3"} {
+		if _, err := parsePrice(value, false); err == nil {
+			t.Errorf("parsePrice(%q) expected an error", value)
+		}
+	}
+}
+
+func TestRecordMerge(t *testing.T) {
+	existing := item.Item{
+		ID:              "abc",
+		Name:            "PS5",
+		Category:        "Games",
+		Producer:        "Sony",
+		MaxPrice:        3500,
+		ScrapingSources: []string{"Amazon"},
+	}
+
+	record := newRecord(2)
+	for field, value := range map[Field]string{
+		FieldMaxPrice: "3200",
+		FieldSources:  "amazon, mercado livre",
+		FieldCategory: "",
+	} {
+		if err := record.set(field, value, false, ","); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	merged := record.merge(existing)
+	want := existing
+	want.MaxPrice = 3200
+	want.ScrapingSources = []string{"Amazon", "Mercado Livre"}
+	if !reflect.DeepEqual(merged, want) {
+		t.Errorf("merge = %+v, want %+v", merged, want)
+	}
+
+	diff := changes(existing, merged)
+	wantDiff := []string{`max_price "3500" -> "3200"`, `sources "Amazon" -> "Amazon,Mercado Livre"`}
+	if !reflect.DeepEqual(diff, wantDiff) {
+		t.Errorf("changes = %q, want %q", diff, wantDiff)
+	}
+}
@@ -0,0 +1,15 @@
+package item
+
+import "time"
+
+// PriceObservation is a price found for an item by a scraping source
+type PriceObservation struct {
+	// ItemID is empty for observations recorded before items had IDs
+	ItemID     string
+	ItemName   string
+	Source     string
+	Price      float64
+	URL        string
+	Seller     string
+	ObservedAt time.Time
+}
@@ -1,10 +1,14 @@
 package item
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"time"
 )
 
 type Item struct {
+	// ID identifies the item and never changes, unlike the name
+	ID              string
 	Name            string
 	Category        string
 	Producer        string
@@ -14,4 +18,20 @@ type Item struct {
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 	MinPrice        float64
+	// RequiredKeywords must all be in a listing title for it to match the item
+	RequiredKeywords []string
+	// ExcludedKeywords reject any listing title containing one of them
+	ExcludedKeywords []string
+	// SourceURLs maps source names to the product page scraped instead of
+	// searching that source
+	SourceURLs map[string]string
+}
+
+// NewID returns a random item ID of 16 hex characters
+func NewID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
 }
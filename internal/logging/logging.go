@@ -0,0 +1,152 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Formats of the log records
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+const (
+	defaultMaxSize    = 10 // megabytes
+	defaultMaxBackups = 3
+)
+
+// Config configures the logger
+type Config struct {
+	Level  slog.Level
+	Format string
+	// File is the path of the log file, empty to log only to the console
+	File string
+	// MaxSize is the size in megabytes the file is rotated at
+	MaxSize int
+	// MaxBackups is the number of rotated files kept
+	MaxBackups int
+}
+
+// ConfigFromViper reads the log section of the config:
+//
+//	log:
+//	  level: info
+//	  format: text
+//	  file: wishlist.log
+//	  max_size: 10
+//	  max_backups: 3
+func ConfigFromViper() (Config, error) {
+	cfg := Config{
+		Level:      slog.LevelInfo,
+		Format:     FormatText,
+		File:       viper.GetString("log.file"),
+		MaxSize:    defaultMaxSize,
+		MaxBackups: defaultMaxBackups,
+	}
+
+	if level := viper.GetString("log.level"); level != "" {
+		if err := cfg.Level.UnmarshalText([]byte(level)); err != nil {
+			return cfg, fmt.Errorf("invalid log.level %q", level)
+		}
+	}
+
+	if format := strings.ToLower(viper.GetString("log.format")); format != "" {
+		if format != FormatText && format != FormatJSON {
+			return cfg, fmt.Errorf("invalid log.format %q, expected text or json", format)
+		}
+		cfg.Format = format
+	}
+
+	if viper.IsSet("log.max_size") {
+		cfg.MaxSize = viper.GetInt("log.max_size")
+	}
+	if viper.IsSet("log.max_backups") {
+		cfg.MaxBackups = viper.GetInt("log.max_backups")
+	}
+
+	return cfg, nil
+}
+
+// New creates a logger writing to the config file, if any, and to console,
+// if not nil. The returned closer closes the file.
+func New(cfg Config, console io.Writer) (*slog.Logger, io.Closer, error) {
+	var handlers []slog.Handler
+	var closer io.Closer = nopCloser{}
+
+	if cfg.File != "" {
+		file, err := NewRotatingFile(cfg.File, int64(cfg.MaxSize)*1024*1024, cfg.MaxBackups)
+		if err != nil {
+			return nil, nil, err
+		}
+		handlers = append(handlers, newHandler(file, cfg))
+		closer = file
+	}
+
+	if console != nil {
+		handlers = append(handlers, newHandler(console, cfg))
+	}
+
+	return slog.New(multiHandler(handlers)), closer, nil
+}
+
+// Discard returns a logger dropping every record
+func Discard() *slog.Logger {
+	return slog.New(multiHandler(nil))
+}
+
+func newHandler(w io.Writer, cfg Config) slog.Handler {
+	options := &slog.HandlerOptions{Level: cfg.Level}
+	if cfg.Format == FormatJSON {
+		return slog.NewJSONHandler(w, options)
+	}
+	return slog.NewTextHandler(w, options)
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
+
+// multiHandler sends the records to every handler enabled for their level
+type multiHandler []slog.Handler
+
+func (m multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range m {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m multiHandler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	for _, h := range m {
+		if h.Enabled(ctx, record.Level) {
+			errs = append(errs, h.Handle(ctx, record.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(multiHandler, len(m))
+	for i, h := range m {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (m multiHandler) WithGroup(name string) slog.Handler {
+	handlers := make(multiHandler, len(m))
+	for i, h := range m {
+		handlers[i] = h.WithGroup(name)
+	}
+	return handlers
+}
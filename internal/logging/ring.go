@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"strings"
+	"sync"
+)
+
+// Ring keeps the last lines written to it, used to show recent logs in the TUI
+type Ring struct {
+	mu     sync.Mutex
+	lines  []string
+	size   int
+	notify func()
+}
+
+// NewRing creates a ring keeping size lines
+func NewRing(size int) *Ring {
+	return &Ring{size: size}
+}
+
+// SetNotify sets a function called after every write, outside the lock
+func (r *Ring) SetNotify(notify func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.notify = notify
+}
+
+// Write adds the lines of p, dropping the oldest ones
+func (r *Ring) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		r.lines = append(r.lines, line)
+	}
+	if extra := len(r.lines) - r.size; extra > 0 {
+		r.lines = append(r.lines[:0], r.lines[extra:]...)
+	}
+	notify := r.notify
+	r.mu.Unlock()
+
+	if notify != nil {
+		notify()
+	}
+	return len(p), nil
+}
+
+// Lines returns the kept lines, oldest first
+func (r *Ring) Lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.lines...)
+}
@@ -0,0 +1,143 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// RotatingFile is a log file renamed to FILE.1 once it reaches its maximum
+// size, older files are shifted to FILE.2, FILE.3 and so on
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewRotatingFile opens the file for appending. A zero maxSize never rotates.
+func NewRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	f := &RotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write writes p, rotating the file first when p does not fit
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		// a failed rotation could not open the file again either
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	var rotateErr error
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		rotateErr = f.rotate()
+	}
+	if f.file == nil {
+		return 0, rotateErr
+	}
+
+	// a failed rotation still writes p to the original file
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	if err == nil {
+		err = rotateErr
+	}
+	return n, err
+}
+
+// Close closes the file
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Close()
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("error opening log file: %v", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("error opening log file: %v", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// rotate shifts the backups, dropping the oldest, and starts a new file. When
+// that fails the original path is opened again, so the following writes are
+// still logged, and the error is returned.
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return f.reopen(err)
+	}
+
+	if err := f.shift(); err != nil {
+		return f.reopen(err)
+	}
+	if err := f.open(); err != nil {
+		// put the current file back in place
+		if f.maxBackups > 0 {
+			os.Rename(f.backup(1), f.path)
+		}
+		return f.reopen(err)
+	}
+	return nil
+}
+
+// shift renames the file to FILE.1 and the backups to the next number, or
+// removes the file without backups
+func (f *RotatingFile) shift() error {
+	if f.maxBackups > 0 {
+		os.Remove(f.backup(f.maxBackups))
+		for i := f.maxBackups - 1; i >= 1; i-- {
+			os.Rename(f.backup(i), f.backup(i+1))
+		}
+		if err := os.Rename(f.path, f.backup(1)); err != nil {
+			return fmt.Errorf("error rotating log file: %v", err)
+		}
+	} else if err := os.Remove(f.path); err != nil {
+		return fmt.Errorf("error rotating log file: %v", err)
+	}
+	return nil
+}
+
+// reopen opens the original path again after a failed rotation and returns
+// the rotation error
+func (f *RotatingFile) reopen(err error) error {
+	if openErr := f.open(); openErr != nil {
+		f.file = nil
+		return errors.Join(err, openErr)
+	}
+	return err
+}
+
+func (f *RotatingFile) backup(n int) string {
+	return fmt.Sprintf("%s.%d", f.path, n)
+}
@@ -0,0 +1,86 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(%s) error = %v", path, err)
+	}
+	return string(data)
+}
+
+func TestRotatingFileRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wishlist.log")
+	f, err := NewRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("NewRotatingFile() error = %v", err)
+	}
+	defer f.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Write(%q) error = %v", line, err)
+		}
+	}
+
+	want := map[string]string{
+		path:        "fourth\n",
+		path + ".1": "third\n",
+		path + ".2": "second\n",
+	}
+	for file, content := range want {
+		if got := readFile(t, file); got != content {
+			t.Errorf("%s = %q, want %q", filepath.Base(file), got, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("%s.3 exists, want at most 2 backups", filepath.Base(path))
+	}
+}
+
+func TestRotatingFileKeepsLoggingAfterFailedRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wishlist.log")
+	f, err := NewRotatingFile(path, 10, 1)
+	if err != nil {
+		t.Fatalf("NewRotatingFile() error = %v", err)
+	}
+	defer f.Close()
+
+	// a non empty directory in place of the backup makes the rename fail
+	if err := os.MkdirAll(filepath.Join(path+".1", "keep"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.Write([]byte("first\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	n, err := f.Write([]byte("second\n"))
+	if err == nil {
+		t.Errorf("Write() after a failed rotation returned no error")
+	}
+	if n != len("second\n") {
+		t.Errorf("Write() after a failed rotation wrote %d bytes, want %d", n, len("second\n"))
+	}
+
+	// once the backup can be written the file rotates again
+	if err := os.RemoveAll(path + ".1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("third\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	if got, want := readFile(t, path+".1"), "first\nsecond\n"; got != want {
+		t.Errorf("backup = %q, want %q", got, want)
+	}
+	if got, want := readFile(t, path), "third\n"; got != want {
+		t.Errorf("log file = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,132 @@
+package menu
+
+import (
+	"fmt"
+
+	"github.com/WellyngtonF/WishListCLI/internal/formComponents"
+	"github.com/WellyngtonF/WishListCLI/internal/repository"
+	"github.com/awesome-gocui/gocui"
+)
+
+const (
+	backupsTableName = "Backups"
+	restoreModalName = "ConfirmRestore"
+)
+
+// backupsScreen lists the backups and restores the selected one
+type backupsScreen struct {
+	repo   *repository.Repository
+	view   *gocui.View
+	table  *formComponents.Table
+	modal  *formComponents.Modal
+	status string
+}
+
+// HandleBackups shows the backups newest first, Enter restores the selected
+// one after a confirmation
+func HandleBackups(g *gocui.Gui, v *gocui.View, repo *repository.Repository) error {
+	rows, err := backupRows(repo)
+	if err != nil {
+		return err
+	}
+
+	s := &backupsScreen{repo: repo, view: v}
+
+	x0, y0, x1, y1 := v.Dimensions()
+	s.table = formComponents.NewTable(g, backupsTableName, x0+1, y0+3, x1-1, y1-1).
+		AddColumn("ID", 20).
+		AddColumn("Created", 20).
+		AddColumn("Reason", 10).
+		AddColumn("Size", 10).
+		SetEmptyText("No backups yet, they are taken before items are updated, deleted or imported.").
+		SetRows(rows).
+		AddHandler(gocui.KeyEnter, s.confirm)
+
+	setScreen(g, func(g *gocui.Gui) {
+		if s.modal != nil {
+			s.modal.Close()
+		}
+		s.table.Close()
+	})
+
+	v.Title = "Backups"
+	s.render()
+
+	s.table.Draw()
+	s.table.Focus()
+	return nil
+}
+
+// backupRows returns the table rows of the backups keyed by backup ID
+func backupRows(repo *repository.Repository) ([]formComponents.TableRow, error) {
+	backups, err := repo.ListBackups()
+	if err != nil {
+		return nil, fmt.Errorf("error listing backups: %v", err)
+	}
+
+	rows := make([]formComponents.TableRow, 0, len(backups))
+	for _, backup := range backups {
+		rows = append(rows, formComponents.TableRow{
+			Key: backup.ID,
+			Cells: []string{
+				backup.ID,
+				backup.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+				backup.Reason,
+				fmt.Sprintf("%d", backup.Size),
+			},
+		})
+	}
+	return rows, nil
+}
+
+// render write the key help and the status line above the table
+func (s *backupsScreen) render() {
+	s.view.Clear()
+	fmt.Fprintln(s.view, tableHelp("Enter restore"))
+	fmt.Fprintln(s.view, s.status)
+}
+
+// confirm asks to restore the selected backup
+func (s *backupsScreen) confirm(g *gocui.Gui, v *gocui.View) error {
+	row, ok := s.table.GetSelected()
+	if !ok {
+		return nil
+	}
+
+	message := fmt.Sprintf("Replace the wishlist with the backup of %s?", row.Cells[1])
+	s.modal = formComponents.NewModal(g, restoreModalName, "Restore", message).
+		SetConfirm("Restore", func(g *gocui.Gui, v *gocui.View) error {
+			s.modal = nil
+			return s.restore(row.Key)
+		}).
+		SetCancel("Cancel", func(g *gocui.Gui, v *gocui.View) error {
+			s.modal = nil
+			s.table.Focus()
+			return nil
+		})
+	s.modal.Draw()
+	return nil
+}
+
+// restore restores the backup, the replaced wishlist is backed up first
+func (s *backupsScreen) restore(id string) error {
+	replaced, err := s.repo.RestoreBackup(id)
+	switch {
+	case err != nil:
+		s.status = fmt.Sprintf("Error restoring backup: %v", err)
+	case replaced != nil:
+		s.status = fmt.Sprintf("Restored backup %s, the previous wishlist was saved as backup %s.", id, replaced.ID)
+	default:
+		s.status = fmt.Sprintf("Restored backup %s.", id)
+	}
+
+	rows, err := backupRows(s.repo)
+	if err != nil {
+		return err
+	}
+
+	s.table.SetRows(rows)
+	s.table.Focus()
+	s.render()
+	return nil
+}
@@ -0,0 +1,204 @@
+package menu
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/WellyngtonF/WishListCLI/internal/formComponents"
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	"github.com/WellyngtonF/WishListCLI/internal/repository"
+	"github.com/awesome-gocui/gocui"
+)
+
+const (
+	deleteModalName = "Confirm"
+	// undoWindow is how long deleted items can be restored
+	undoWindow = 10 * time.Second
+)
+
+// deleteScreen is the wishlist table where items are marked and deleted
+type deleteScreen struct {
+	repo  *repository.Repository
+	view  *gocui.View
+	table *formComponents.Table
+	modal *formComponents.Modal
+	// query lists the items in the order the table is sorted
+	query repository.Query
+
+	// deleted holds the items of the last deletion until the undo window ends
+	deleted []item.Item
+	// generation identifies the last deletion, older undo timers are ignored
+	generation int
+	status     string
+}
+
+// HandleDeleteItem shows the wishlist with multi select, deleting the marked
+// or selected items after a confirmation. The deletion can be undone for a while.
+func HandleDeleteItem(g *gocui.Gui, v *gocui.View, repo *repository.Repository) error {
+	s := &deleteScreen{repo: repo, view: v}
+
+	rows, err := wishlistRows(repo, s.query)
+	if err != nil {
+		return err
+	}
+
+	x0, y0, x1, y1 := v.Dimensions()
+	s.table = newWishlistTable(g, repo, &s.query, x0+1, y0+3, x1-1, y1-1).
+		SetMultiSelect(true).
+		SetRows(rows).
+		AddHandler(gocui.KeyDelete, s.confirm).
+		AddHandler('d', s.confirm).
+		AddHandler('u', s.undo)
+
+	setScreen(g, func(g *gocui.Gui) {
+		if s.modal != nil {
+			s.modal.Close()
+		}
+		s.table.Close()
+		s.deleted = nil
+		s.generation++
+	})
+
+	v.Title = "Delete Item from Wishlist"
+	s.render()
+
+	s.table.Draw()
+	s.table.Focus()
+	return nil
+}
+
+// render write the key help and the status line above the table
+func (s *deleteScreen) render() {
+	s.view.Clear()
+	fmt.Fprintln(s.view, tableHelp("Space mark", "d/Delete delete", "u undo"))
+	fmt.Fprintln(s.view, s.status)
+}
+
+// targets returns the marked rows or the selected one when none is marked
+func (s *deleteScreen) targets() []formComponents.TableRow {
+	if marked := s.table.GetMarked(); len(marked) > 0 {
+		return marked
+	}
+	if row, ok := s.table.GetSelected(); ok {
+		return []formComponents.TableRow{row}
+	}
+	return nil
+}
+
+// confirm asks to delete the targets
+func (s *deleteScreen) confirm(g *gocui.Gui, v *gocui.View) error {
+	targets := s.targets()
+	if len(targets) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(targets))
+	names := make([]string, len(targets))
+	for i, row := range targets {
+		ids[i] = row.Key
+		names[i] = row.Cells[0]
+	}
+
+	message := fmt.Sprintf("Delete %q?", names[0])
+	if len(names) > 1 {
+		message = fmt.Sprintf("Delete %d items?\n%s", len(names), strings.Join(names, ", "))
+	}
+
+	s.modal = formComponents.NewModal(g, deleteModalName, "Delete", message).
+		SetConfirm("Delete", func(g *gocui.Gui, v *gocui.View) error {
+			s.modal = nil
+			return s.delete(g, ids)
+		}).
+		SetCancel("Cancel", func(g *gocui.Gui, v *gocui.View) error {
+			s.modal = nil
+			s.table.Focus()
+			return nil
+		})
+	s.modal.Draw()
+	return nil
+}
+
+// delete deletes the items with the given IDs, keeping them for undo
+func (s *deleteScreen) delete(g *gocui.Gui, ids []string) error {
+	// a single backup for all the items
+	var deleted []item.Item
+	var errs []error
+	err := s.repo.WithBackup("delete", func() error {
+		for _, id := range ids {
+			itm, err := s.repo.GetItem(id)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %v", id, err))
+				continue
+			}
+			if err := s.repo.DeleteItem(id); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %v", itm.Name, err))
+				continue
+			}
+			deleted = append(deleted, *itm)
+		}
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	s.deleted = deleted
+	s.generation++
+	s.status = fmt.Sprintf("Deleted %d item(s), press u within %s to undo.", len(deleted), undoWindow)
+	if err := errors.Join(errs...); err != nil {
+		s.status = fmt.Sprintf("Error deleting items: %v", err)
+	}
+
+	generation := s.generation
+	time.AfterFunc(undoWindow, func() {
+		g.Update(func(g *gocui.Gui) error {
+			if generation == s.generation && s.deleted != nil {
+				s.deleted = nil
+				s.status = ""
+				s.render()
+			}
+			return nil
+		})
+	})
+
+	s.table.ClearMarks()
+	return s.refresh(g)
+}
+
+// undo restores the items of the last deletion
+func (s *deleteScreen) undo(g *gocui.Gui, v *gocui.View) error {
+	if len(s.deleted) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, itm := range s.deleted {
+		if err := s.repo.RestoreItem(itm); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", itm.Name, err))
+		}
+	}
+
+	s.status = fmt.Sprintf("Restored %d item(s).", len(s.deleted)-len(errs))
+	if err := errors.Join(errs...); err != nil {
+		s.status = fmt.Sprintf("Error restoring items: %v", err)
+	}
+	s.deleted = nil
+	s.generation++
+
+	return s.refresh(g)
+}
+
+// refresh reloads the table rows and the status line
+func (s *deleteScreen) refresh(g *gocui.Gui) error {
+	rows, err := wishlistRows(s.repo, s.query)
+	if err != nil {
+		return err
+	}
+
+	s.table.SetRows(rows)
+	s.table.Focus()
+	s.render()
+	return nil
+}
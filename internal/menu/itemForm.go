@@ -0,0 +1,233 @@
+package menu
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/WellyngtonF/WishListCLI/internal/formComponents"
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	"github.com/WellyngtonF/WishListCLI/internal/repository"
+	"github.com/WellyngtonF/WishListCLI/internal/scraper/sources"
+	"github.com/awesome-gocui/gocui"
+)
+
+const itemFormName = "itemForm"
+
+// showItemForm shows the item form prefilled with itm. On submit the fields
+// are copied to itm and passed to save, errors are shown next to the button.
+// Categories already in the wishlist and the registered sources are offered
+// as choices.
+func showItemForm(g *gocui.Gui, v *gocui.View, repo *repository.Repository, title string, itm item.Item, save func(item.Item) error) error {
+	categories, err := repo.Categories()
+	if err != nil {
+		return fmt.Errorf("error listing categories: %v", err)
+	}
+
+	v.Title = title
+
+	form := formComponents.NewForm(g, itemFormName, 30, 0, 10)
+
+	nameInput := form.AddInputField("Name", 30).
+		AddValidate("Name is required", func(value string) bool {
+			return len(strings.TrimSpace(value)) > 0
+		}).
+		SetText(itm.Name)
+
+	categorySelect := form.AddSelect("Category", 30).
+		SetEditable(true).
+		AddOptions(categories...).
+		SetSelected(itm.Category)
+
+	producerInput := form.AddInputField("Producer", 30).
+		SetText(itm.Producer)
+
+	maxPriceInput := form.AddInputField("Max Price", 15).
+		AddValidate("Invalid price format", validPrice).
+		SetText(formatPrice(itm.MaxPrice))
+
+	minPriceInput := form.AddInputField("Min Price", 15).
+		AddValidate("Invalid price format", validPrice).
+		SetText(formatPrice(itm.MinPrice))
+
+	checked, unknown := itemSources(itm)
+	sourcesCheckBox := form.AddCheckBox("Sources").
+		AddOptions(sources.Names()...).
+		AddOptions(unknown...).
+		AddValidate("Uncheck the unknown sources", func(value string) bool {
+			for _, source := range splitKeywords(value) {
+				if _, ok := sources.Lookup(source); !ok {
+					return false
+				}
+			}
+			return true
+		}).
+		SetChecked(checked...)
+
+	requiredInput := form.AddInputField("Required", 40).
+		SetText(strings.Join(itm.RequiredKeywords, ","))
+
+	excludedInput := form.AddInputField("Excluded", 40).
+		SetText(strings.Join(itm.ExcludedKeywords, ","))
+
+	urlInput := form.AddInputField("URL", 40).
+		SetText(itm.URL)
+
+	pagesInput := form.AddInputField("Pages", 40).
+		AddValidate("Expected Source=URL|Source=URL with pages of the sources", func(value string) bool {
+			_, err := parseSourceURLs(value)
+			return err == nil
+		}).
+		SetText(formatSourceURLs(itm.SourceURLs))
+
+	form.SetSubmit("Submit", func(form *formComponents.Form) error {
+		maxPrice, _ := strconv.ParseFloat(maxPriceInput.GetFieldText(), 64)
+		minPrice, _ := strconv.ParseFloat(minPriceInput.GetFieldText(), 64)
+
+		itm.Name = strings.TrimSpace(nameInput.GetFieldText())
+		itm.Category = strings.TrimSpace(categorySelect.GetSelected())
+		itm.Producer = producerInput.GetFieldText()
+		itm.MaxPrice = maxPrice
+		itm.MinPrice = minPrice
+		itm.ScrapingSources = sourcesCheckBox.GetChecked()
+		itm.RequiredKeywords = splitKeywords(requiredInput.GetFieldText())
+		itm.ExcludedKeywords = splitKeywords(excludedInput.GetFieldText())
+		itm.URL = strings.TrimSpace(urlInput.GetFieldText())
+		itm.SourceURLs, _ = parseSourceURLs(pagesInput.GetFieldText())
+		itm.ScrapingSources = withPageSources(itm.ScrapingSources, itm.SourceURLs)
+
+		if err := save(itm); err != nil {
+			return fmt.Errorf("error saving item: %v", err)
+		}
+
+		CloseScreen(g)
+
+		v.Clear()
+		fmt.Fprintf(v, "Item %q saved.\n", itm.Name)
+
+		g.SetCurrentView("menu")
+		return nil
+	}).
+		SetCancel(func(form *formComponents.Form) error {
+			CloseScreen(g)
+
+			g.SetCurrentView("menu")
+			return nil
+		})
+
+	setScreen(g, func(g *gocui.Gui) {
+		form.Close()
+	})
+
+	form.Draw()
+	return nil
+}
+
+// validPrice reports whether value is a number
+func validPrice(value string) bool {
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil
+}
+
+// itemSources returns the sources of the item by their registered name,
+// sources that are not registered are returned as unknown so they can be
+// unchecked
+func itemSources(itm item.Item) (checked, unknown []string) {
+	for _, name := range itm.ScrapingSources {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		if source, ok := sources.Lookup(name); ok {
+			checked = append(checked, source.Name())
+		} else {
+			checked = append(checked, name)
+			unknown = append(unknown, name)
+		}
+	}
+	return checked, unknown
+}
+
+// formatPrice formats a price for an input field without trailing zeros
+func formatPrice(price float64) string {
+	return strconv.FormatFloat(price, 'f', -1, 64)
+}
+
+// splitKeywords splits a comma separated list of keywords ignoring empty ones
+func splitKeywords(text string) []string {
+	var keywords []string
+	for _, keyword := range strings.Split(text, ",") {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+	return keywords
+}
+
+// formatSourceURLs formats the product pages like "Amazon=https://...|Mercado Livre=https://...",
+// sorted by source
+func formatSourceURLs(urls map[string]string) string {
+	entries := make([]string, 0, len(urls))
+	for source, page := range urls {
+		entries = append(entries, source+"="+page)
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, "|")
+}
+
+// parseSourceURLs parses the product pages written by formatSourceURLs, keyed
+// by the registered source name. Every page must belong to its source.
+func parseSourceURLs(text string) (map[string]string, error) {
+	var urls map[string]string
+	for _, entry := range strings.Split(text, "|") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		name, page, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid page %q, expected Source=URL", entry)
+		}
+
+		src, ok := sources.Lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("unsupported source: %s", name)
+		}
+		page = strings.TrimSpace(page)
+		if !src.HandlesURL(page) {
+			return nil, fmt.Errorf("%s is not a page of %s", page, src.Name())
+		}
+
+		if urls == nil {
+			urls = make(map[string]string)
+		}
+		urls[src.Name()] = page
+	}
+	return urls, nil
+}
+
+// withPageSources adds the sources with a product page missing from the
+// checked sources
+func withPageSources(checked []string, urls map[string]string) []string {
+	names := make([]string, 0, len(urls))
+	for name := range urls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		found := false
+		for _, source := range checked {
+			if source == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			checked = append(checked, name)
+		}
+	}
+	return checked
+}
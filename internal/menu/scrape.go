@@ -0,0 +1,183 @@
+package menu
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/WellyngtonF/WishListCLI/internal/alert"
+	"github.com/WellyngtonF/WishListCLI/internal/formComponents"
+	"github.com/WellyngtonF/WishListCLI/internal/repository"
+	"github.com/WellyngtonF/WishListCLI/internal/scraper"
+	"github.com/awesome-gocui/gocui"
+)
+
+const scrapeTableName = "Scraping"
+
+// scrapeScreen shows the state of every job of a running scrape
+type scrapeScreen struct {
+	view   *gocui.View
+	table  *formComponents.Table
+	jobs   []scraper.Job
+	rows   []formComponents.TableRow
+	cancel context.CancelFunc
+
+	start     time.Time
+	elapsed   time.Duration
+	done      int
+	failed    int
+	cancelled bool
+	finished  bool
+	// closed is set when the screen is closed, later updates are ignored
+	closed bool
+}
+
+// HandleRunScraping scrapes every item of the wishlist in the background,
+// showing the progress of each item and source. Esc or c cancels the scrape.
+func HandleRunScraping(g *gocui.Gui, v *gocui.View, repo *repository.Repository) error {
+	items, err := repo.ListItems()
+	if err != nil {
+		return fmt.Errorf("error listing items: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &scrapeScreen{
+		view:   v,
+		jobs:   scraper.Jobs(items),
+		cancel: cancel,
+		start:  time.Now(),
+	}
+
+	s.rows = make([]formComponents.TableRow, len(s.jobs))
+	for i, job := range s.jobs {
+		s.rows[i] = formComponents.TableRow{
+			Key:   strconv.Itoa(i),
+			Cells: []string{job.Item.Name, job.Source, string(scraper.StatusQueued), "-", ""},
+		}
+	}
+
+	x0, y0, x1, y1 := v.Dimensions()
+	s.table = formComponents.NewTable(g, scrapeTableName, x0+1, y0+3, x1-1, y1-1).
+		AddColumn("Item", 24).
+		AddColumn("Source", 14).
+		AddColumn("Status", 8).
+		AddColumn("Price", 10).
+		AddColumn("Details", 60).
+		SetEmptyText("No item has a scraping source.").
+		SetRows(s.tableRows()).
+		AddHandler(gocui.KeyEsc, s.stop).
+		AddHandler('c', s.stop)
+
+	setScreen(g, func(g *gocui.Gui) {
+		s.closed = true
+		s.cancel()
+		s.table.Close()
+	})
+
+	v.Title = "Run Web Scraping"
+	s.render()
+
+	s.table.Draw()
+	s.table.Focus()
+
+	runner := scraper.NewRunner(repo).
+		SetAlerts(alert.NewEvaluatorFromConfig()).
+		OnProgress(func(p scraper.Progress) {
+			g.Update(func(g *gocui.Gui) error {
+				s.update(p)
+				return nil
+			})
+		})
+
+	go func() {
+		runner.RunJobs(ctx, s.jobs)
+		g.Update(func(g *gocui.Gui) error {
+			s.finished = true
+			s.elapsed = time.Since(s.start)
+			s.cancel()
+			s.render()
+			return nil
+		})
+	}()
+
+	return nil
+}
+
+// update shows the new state of a job
+func (s *scrapeScreen) update(p scraper.Progress) {
+	if s.closed {
+		return
+	}
+
+	cells := s.rows[p.Index].Cells
+	cells[2] = string(p.Status)
+
+	if result := p.Result; result != nil {
+		s.done++
+		switch best := result.Best(); {
+		case p.Status == scraper.StatusFailed:
+			s.failed++
+			cells[4] = fmt.Sprint(result.Err)
+		case best != nil:
+			cells[3] = fmt.Sprintf("%.2f", best.Price)
+			cells[4] = best.Seller
+			if len(result.Alerts) > 0 {
+				cells[4] = "ALERT: " + result.Alerts[0].Message()
+			}
+		}
+	}
+
+	s.table.SetRows(s.tableRows())
+	s.render()
+}
+
+// tableRows returns a copy of the rows, the table sorts the slice it is given
+func (s *scrapeScreen) tableRows() []formComponents.TableRow {
+	return append([]formComponents.TableRow(nil), s.rows...)
+}
+
+// stop cancels the scrape, the jobs not finished yet fail
+func (s *scrapeScreen) stop(g *gocui.Gui, v *gocui.View) error {
+	if !s.finished {
+		s.cancelled = true
+		s.cancel()
+		s.render()
+	}
+	return nil
+}
+
+// render write the key help and the progress bar above the table
+func (s *scrapeScreen) render() {
+	if s.closed {
+		return
+	}
+
+	s.view.Clear()
+	fmt.Fprintln(s.view, tableHelp("Esc/c cancel"))
+
+	state, elapsed := "running", time.Since(s.start)
+	switch {
+	case s.finished && s.cancelled:
+		state, elapsed = "cancelled", s.elapsed
+	case s.finished:
+		state, elapsed = "finished", s.elapsed
+	case s.cancelled:
+		state = "cancelling"
+	}
+
+	total := len(s.jobs)
+	fmt.Fprintf(s.view, "%s %d/%d  %d failed  %s in %s\n",
+		progressBar(s.done, total, 30), s.done, total, s.failed,
+		state, elapsed.Round(time.Second))
+}
+
+// progressBar returns a bar of width characters filled in proportion to done/total
+func progressBar(done, total, width int) string {
+	filled := width
+	if total > 0 {
+		filled = done * width / total
+	}
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", width-filled) + "]"
+}
@@ -0,0 +1,23 @@
+package menu
+
+import "github.com/awesome-gocui/gocui"
+
+// closeScreen closes the views and keybindings of the screen shown in the main view
+var closeScreen func(g *gocui.Gui)
+
+// setScreen closes the current screen and registers how to close the new one
+func setScreen(g *gocui.Gui, close func(g *gocui.Gui)) {
+	CloseScreen(g)
+	closeScreen = close
+}
+
+// CloseScreen closes the screen opened by the last menu option, if any
+func CloseScreen(g *gocui.Gui) {
+	if closeScreen == nil {
+		return
+	}
+
+	close := closeScreen
+	closeScreen = nil
+	close(g)
+}
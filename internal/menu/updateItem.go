@@ -0,0 +1,97 @@
+package menu
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/WellyngtonF/WishListCLI/internal/formComponents"
+	"github.com/WellyngtonF/WishListCLI/internal/repository"
+	"github.com/awesome-gocui/gocui"
+)
+
+const searchInputName = "Search"
+
+// HandleUpdateItem shows the wishlist items matching a fuzzy name search,
+// Enter on an item opens it in the item form
+func HandleUpdateItem(g *gocui.Gui, v *gocui.View, repo *repository.Repository) error {
+	x0, y0, x1, y1 := v.Dimensions()
+
+	var query repository.Query
+	rows, err := wishlistRows(repo, query)
+	if err != nil {
+		return err
+	}
+
+	table := newWishlistTable(g, repo, &query, x0+1, y0+3, x1-1, y1-1).
+		SetRows(rows)
+
+	search := formComponents.NewInputField(g, searchInputName, x0+1, y0, 8, 30).
+		SetOnChange(func(text string) {
+			query.Search = text
+			rows, err := wishlistRows(repo, query)
+			if err != nil {
+				// keep the rows of the last search
+				slog.Error("error searching items", "search", text, "error", err)
+				return
+			}
+			table.SetRows(rows)
+		})
+
+	focusTable := func(g *gocui.Gui, v *gocui.View) error {
+		table.Focus()
+		return nil
+	}
+	search.AddHandler(gocui.KeyEnter, focusTable).
+		AddHandler(gocui.KeyArrowDown, focusTable).
+		AddHandler(gocui.KeyTab, focusTable)
+
+	table.AddHandler(gocui.KeyEnter, editSelected(table, v, repo)).
+		AddHandler('/', func(g *gocui.Gui, tv *gocui.View) error {
+			search.Focus()
+			return nil
+		})
+
+	setScreen(g, func(g *gocui.Gui) {
+		search.Close()
+		table.Close()
+	})
+
+	v.Title = "Update Item in Wishlist"
+	writeUpdateHelp(v, "")
+
+	table.Draw()
+	search.Draw()
+	search.Focus()
+	return nil
+}
+
+// editSelected returns a table handler opening the selected item in the item form
+func editSelected(table *formComponents.Table, v *gocui.View, repo *repository.Repository) formComponents.Handler {
+	return func(g *gocui.Gui, tv *gocui.View) error {
+		row, ok := table.GetSelected()
+		if !ok {
+			return nil
+		}
+		return editItem(g, v, repo, row.Key)
+	}
+}
+
+// writeUpdateHelp writes the table keys above the table followed by status
+func writeUpdateHelp(v *gocui.View, status string) {
+	v.Clear()
+	fmt.Fprintf(v, "\n%s\n%s\n", tableHelp("Enter edit", "/ search"), status)
+}
+
+// editItem opens the item with the given ID in the item form. Items that
+// cannot be read, like one deleted by another process, are reported below
+// the table keys and the table is kept.
+func editItem(g *gocui.Gui, v *gocui.View, repo *repository.Repository, id string) error {
+	itm, err := repo.GetItem(id)
+	if err != nil {
+		writeUpdateHelp(v, fmt.Sprintf("Error reading item: %v", err))
+		return nil
+	}
+
+	v.Clear()
+	return showItemForm(g, v, repo, "Update Item in Wishlist", *itm, repo.UpdateItem)
+}
@@ -0,0 +1,136 @@
+package menu
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/WellyngtonF/WishListCLI/internal/formComponents"
+	"github.com/WellyngtonF/WishListCLI/internal/repository"
+	"github.com/awesome-gocui/gocui"
+)
+
+const wishlistTableName = "Wishlist"
+
+// HandleViewWishlist shows every item of the wishlist in a sortable table,
+// Enter on an item opens it in the item form and f shows the deals only
+func HandleViewWishlist(g *gocui.Gui, v *gocui.View, repo *repository.Repository) error {
+	var query repository.Query
+	rows, err := wishlistRows(repo, query)
+	if err != nil {
+		return err
+	}
+
+	x0, y0, x1, y1 := v.Dimensions()
+	table := newWishlistTable(g, repo, &query, x0+1, y0+2, x1-1, y1-1).
+		SetRows(rows)
+	table.AddHandler(gocui.KeyEnter, editSelected(table, v, repo)).
+		AddHandler('f', func(g *gocui.Gui, tv *gocui.View) error {
+			query.HasDeal = !query.HasDeal
+			rows, err := wishlistRows(repo, query)
+			if err != nil {
+				return err
+			}
+
+			v.Title = "View Wishlist"
+			if query.HasDeal {
+				v.Title += " (deals)"
+			}
+			table.SetRows(rows)
+			return nil
+		})
+
+	setScreen(g, func(g *gocui.Gui) {
+		table.Close()
+	})
+
+	v.Title = "View Wishlist"
+	fmt.Fprintln(v, tableHelp("Enter edit", "f deals only"))
+
+	table.Draw()
+	table.Focus()
+	return nil
+}
+
+// wishlistSortFields are the query fields sorting the wishlist table
+// columns, in column order
+var wishlistSortFields = []repository.SortField{
+	repository.SortName,
+	repository.SortCategory,
+	repository.SortProducer,
+	repository.SortMinPrice,
+	repository.SortMaxPrice,
+	repository.SortLastPrice,
+	repository.SortBestSource,
+	repository.SortUpdated,
+}
+
+// newWishlistTable creates the wishlist table, rows are keyed by item ID.
+// Sorting a column sorts the query and lists its items again.
+func newWishlistTable(g *gocui.Gui, repo *repository.Repository, query *repository.Query, x, y, w, h int) *formComponents.Table {
+	table := formComponents.NewTable(g, wishlistTableName, x, y, w, h)
+	return table.
+		AddColumn("Name", 24).
+		AddColumn("Category", 12).
+		AddColumn("Producer", 12).
+		AddColumn("Min Price", 10).
+		AddColumn("Max Price", 10).
+		AddColumn("Last Price", 10).
+		AddColumn("Best Source", 14).
+		AddColumn("Updated", 16).
+		SetEmptyText("The wishlist is empty, add an item from the menu.").
+		SetSorter(func(column int, desc bool) {
+			query.SortBy, query.Desc = "", desc
+			if column >= 0 && column < len(wishlistSortFields) {
+				query.SortBy = wishlistSortFields[column]
+			}
+
+			rows, err := wishlistRows(repo, *query)
+			if err != nil {
+				slog.Error("error sorting items", "sort", query.SortBy, "error", err)
+				return
+			}
+			table.SetRows(rows)
+		})
+}
+
+// wishlistRows returns the table rows of the items selected by the query,
+// keyed by item ID
+func wishlistRows(repo *repository.Repository, q repository.Query) ([]formComponents.TableRow, error) {
+	results, err := repo.QueryPrices(q)
+	if err != nil {
+		return nil, fmt.Errorf("error listing items: %v", err)
+	}
+
+	rows := make([]formComponents.TableRow, 0, len(results))
+	for _, result := range results {
+		itm := result.Item
+		lastPrice, bestSource := "-", "-"
+		if result.Best != nil {
+			lastPrice = fmt.Sprintf("%.2f", result.Best.Price)
+			bestSource = result.Best.Source
+		}
+
+		rows = append(rows, formComponents.TableRow{
+			Key: itm.ID,
+			Cells: []string{
+				itm.Name,
+				itm.Category,
+				itm.Producer,
+				fmt.Sprintf("%.2f", itm.MinPrice),
+				fmt.Sprintf("%.2f", itm.MaxPrice),
+				lastPrice,
+				bestSource,
+				itm.UpdatedAt.Local().Format("2006-01-02 15:04"),
+			},
+		})
+	}
+
+	return rows, nil
+}
+
+// tableHelp is the key help shown above the wishlist table
+func tableHelp(extra ...string) string {
+	help := []string{"↑/↓ select", "digits or click header: sort", "←/→ sort column", "r reverse"}
+	return strings.Join(append(help, extra...), "  ")
+}
@@ -0,0 +1,74 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format is how results are written
+type Format string
+
+const (
+	// FormatTable writes tab aligned columns for humans
+	FormatTable Format = "table"
+	// FormatJSON writes a single JSON document
+	FormatJSON Format = "json"
+	// FormatNDJSON writes one JSON object per line
+	FormatNDJSON Format = "ndjson"
+)
+
+// Formats lists the supported formats
+var Formats = []Format{FormatTable, FormatJSON, FormatNDJSON}
+
+// ParseFormat returns the format with the given name, case insensitive
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(strings.TrimSpace(name), string(f)) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported format %q, expected one of %s", name, formatNames())
+}
+
+// String returns the name of the format
+func (f Format) String() string {
+	return string(f)
+}
+
+// Set parses the format name, Format is a flag.Value
+func (f *Format) Set(name string) error {
+	format, err := ParseFormat(name)
+	if err != nil {
+		return err
+	}
+	*f = format
+	return nil
+}
+
+func formatNames() string {
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return strings.Join(names, ", ")
+}
+
+// writeJSON writes v as an indented JSON document
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeNDJSON writes each value on its own line
+func writeNDJSON[T any](w io.Writer, values []T) error {
+	enc := json.NewEncoder(w)
+	for _, v := range values {
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,294 @@
+package output
+
+import (
+	"strings"
+	"time"
+
+	"github.com/WellyngtonF/WishListCLI/internal/alert"
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	"github.com/WellyngtonF/WishListCLI/internal/persistence"
+	"github.com/WellyngtonF/WishListCLI/internal/scraper"
+	"github.com/WellyngtonF/WishListCLI/internal/scraper/sources"
+)
+
+// The types below are the JSON schema of the output. Fields are only ever
+// added, never renamed or removed, so scripts keep working across versions.
+// Lists are never null and times are RFC 3339.
+
+// Item is the JSON form of item.Item
+type Item struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	Category         string    `json:"category"`
+	Producer         string    `json:"producer"`
+	MaxPrice         float64   `json:"max_price"`
+	MinPrice         float64   `json:"min_price"`
+	Sources          []string  `json:"sources"`
+	URL              string    `json:"url"`
+	RequiredKeywords []string  `json:"required_keywords"`
+	ExcludedKeywords []string  `json:"excluded_keywords"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	// SourceURLs maps source names to the product page scraped instead of searching
+	SourceURLs map[string]string `json:"source_urls"`
+}
+
+// ItemDetail is an item with the latest price seen on each of its sources
+type ItemDetail struct {
+	Item
+	LatestPrices []LatestPrice `json:"latest_prices"`
+}
+
+// LatestPrice is the last price recorded for a source, Price and ObservedAt
+// are null when the source was never scraped
+type LatestPrice struct {
+	Source     string     `json:"source"`
+	Price      *float64   `json:"price"`
+	URL        string     `json:"url"`
+	Seller     string     `json:"seller"`
+	ObservedAt *time.Time `json:"observed_at"`
+}
+
+// Status of a scrape result
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// ScrapeResult is the JSON form of scraper.Result
+type ScrapeResult struct {
+	ItemID string `json:"item_id"`
+	Item   string `json:"item"`
+	Source string `json:"source"`
+	// ProductURL is the page scraped, empty when the source was searched
+	ProductURL string `json:"product_url"`
+	// Status is "ok" when a best offer was found, "error" otherwise
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	Best       *Offer    `json:"best"`
+	Offers     []Offer   `json:"offers"`
+	Alerts     []Alert   `json:"alerts"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	DurationMS int64     `json:"duration_ms"`
+}
+
+// Offer is the JSON form of sources.Offer
+type Offer struct {
+	Title        string  `json:"title"`
+	Price        float64 `json:"price"`
+	Currency     string  `json:"currency"`
+	Seller       string  `json:"seller"`
+	ShippingCost float64 `json:"shipping_cost"`
+	FreeShipping bool    `json:"free_shipping"`
+	TotalPrice   float64 `json:"total_price"`
+	// Availability is "in_stock", "out_of_stock" or empty when unknown
+	Availability string `json:"availability"`
+	// Condition is "new", "used" or empty when unknown
+	Condition string  `json:"condition"`
+	Rating    float64 `json:"rating"`
+	URL       string  `json:"url"`
+	Score     float64 `json:"score"`
+	Rejected  string  `json:"rejected"`
+}
+
+// Alert is the JSON form of alert.Event
+type Alert struct {
+	// Kind is "below_max_price" or "price_drop"
+	Kind          string    `json:"kind"`
+	Message       string    `json:"message"`
+	Price         float64   `json:"price"`
+	URL           string    `json:"url"`
+	Seller        string    `json:"seller"`
+	MaxPrice      float64   `json:"max_price"`
+	PreviousPrice float64   `json:"previous_price"`
+	DropPercent   float64   `json:"drop_percent"`
+	At            time.Time `json:"at"`
+}
+
+// Backup is the JSON form of persistence.Backup
+type Backup struct {
+	ID        string    `json:"id"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+	Size      int64     `json:"size"`
+	Path      string    `json:"path"`
+}
+
+// Report is the wishlist written by export -format json
+type Report struct {
+	GeneratedAt time.Time    `json:"generated_at"`
+	Items       []ReportItem `json:"items"`
+}
+
+// ReportItem is an item with its latest prices and the cheapest of them.
+// Delta is the best price minus the maximum price, negative below it; it is
+// null without a best price or a maximum price.
+type ReportItem struct {
+	ItemDetail
+	Best         *LatestPrice `json:"best"`
+	Delta        *float64     `json:"delta"`
+	DeltaPercent *float64     `json:"delta_percent"`
+	Deal         bool         `json:"deal"`
+}
+
+// ImportAction is a change planned by an import, line is 0 for JSON and YAML
+type ImportAction struct {
+	Line   int    `json:"line,omitempty"`
+	Action string `json:"action"`
+	ID     string `json:"id,omitempty"`
+	Name   string `json:"name"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// NewItem converts an item to its JSON form
+func NewItem(itm item.Item) Item {
+	return Item{
+		ID:               itm.ID,
+		Name:             itm.Name,
+		Category:         itm.Category,
+		Producer:         itm.Producer,
+		MaxPrice:         itm.MaxPrice,
+		MinPrice:         itm.MinPrice,
+		Sources:          trimmed(itm.ScrapingSources),
+		URL:              itm.URL,
+		RequiredKeywords: trimmed(itm.RequiredKeywords),
+		ExcludedKeywords: trimmed(itm.ExcludedKeywords),
+		CreatedAt:        itm.CreatedAt,
+		UpdatedAt:        itm.UpdatedAt,
+		SourceURLs:       sourceURLs(itm.SourceURLs),
+	}
+}
+
+// NewBackup converts a backup to its JSON form
+func NewBackup(backup persistence.Backup) Backup {
+	return Backup{
+		ID:        backup.ID,
+		Reason:    backup.Reason,
+		CreatedAt: backup.CreatedAt,
+		Size:      backup.Size,
+		Path:      backup.Path,
+	}
+}
+
+// NewLatestPrice converts the latest observation of a source, nil when the
+// source was never scraped, to its JSON form
+func NewLatestPrice(source string, obs *item.PriceObservation) LatestPrice {
+	price := LatestPrice{Source: source}
+	if obs != nil {
+		observedAt := obs.ObservedAt
+		observed := obs.Price
+		price.Price = &observed
+		price.URL = obs.URL
+		price.Seller = obs.Seller
+		price.ObservedAt = &observedAt
+	}
+	return price
+}
+
+// NewItemDetail converts an item and the latest observation of each source,
+// keyed by source name, to its JSON form
+func NewItemDetail(itm item.Item, latest map[string]*item.PriceObservation) ItemDetail {
+	detail := ItemDetail{
+		Item:         NewItem(itm),
+		LatestPrices: []LatestPrice{},
+	}
+
+	for _, source := range detail.Sources {
+		detail.LatestPrices = append(detail.LatestPrices, NewLatestPrice(source, latest[source]))
+	}
+
+	return detail
+}
+
+// NewScrapeResult converts a runner result to its JSON form
+func NewScrapeResult(result scraper.Result) ScrapeResult {
+	r := ScrapeResult{
+		ItemID:     result.Item.ID,
+		Item:       result.Item.Name,
+		Source:     result.Source,
+		ProductURL: result.URL,
+		Status:     StatusOK,
+		Offers:     []Offer{},
+		Alerts:     []Alert{},
+		StartedAt:  result.StartedAt,
+		FinishedAt: result.FinishedAt,
+		DurationMS: result.FinishedAt.Sub(result.StartedAt).Milliseconds(),
+	}
+
+	if result.Scrape != nil {
+		for _, offer := range result.Scrape.Offers {
+			r.Offers = append(r.Offers, newOffer(offer))
+		}
+	}
+	if best := result.Best(); best != nil {
+		offer := newOffer(*best)
+		r.Best = &offer
+	}
+	for _, event := range result.Alerts {
+		r.Alerts = append(r.Alerts, newAlert(event))
+	}
+
+	switch {
+	case result.Err != nil:
+		r.Status = StatusError
+		r.Error = result.Err.Error()
+	case r.Best == nil:
+		r.Status = StatusError
+		r.Error = "no offer found"
+	}
+
+	return r
+}
+
+func newOffer(offer sources.Offer) Offer {
+	return Offer{
+		Title:        offer.Title,
+		Price:        offer.Price,
+		Currency:     offer.Currency,
+		Seller:       offer.Seller,
+		ShippingCost: offer.ShippingCost,
+		FreeShipping: offer.FreeShipping,
+		TotalPrice:   offer.TotalPrice(),
+		Availability: string(offer.Availability),
+		Condition:    string(offer.Condition),
+		Rating:       offer.Rating,
+		URL:          offer.URL,
+		Score:        offer.Score,
+		Rejected:     offer.Rejected,
+	}
+}
+
+func newAlert(event alert.Event) Alert {
+	return Alert{
+		Kind:          string(event.Kind),
+		Message:       event.Message(),
+		Price:         event.Price,
+		URL:           event.URL,
+		Seller:        event.Seller,
+		MaxPrice:      event.MaxPrice,
+		PreviousPrice: event.PreviousPrice,
+		DropPercent:   event.DropPercent,
+		At:            event.At,
+	}
+}
+
+// trimmed returns the non empty trimmed values, never nil
+func trimmed(values []string) []string {
+	list := []string{}
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// sourceURLs copies the source URLs, an empty object instead of null
+func sourceURLs(urls map[string]string) map[string]string {
+	copied := make(map[string]string, len(urls))
+	for source, url := range urls {
+		copied[source] = url
+	}
+	return copied
+}
@@ -0,0 +1,138 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// WriteItems writes the items as a JSON array, one JSON object per line or a table
+func WriteItems(w io.Writer, format Format, items []Item) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, items)
+	case FormatNDJSON:
+		return writeNDJSON(w, items)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tCATEGORY\tPRODUCER\tMAX PRICE\tMIN PRICE\tSOURCES\tUPDATED")
+	for _, itm := range items {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%.2f\t%.2f\t%s\t%s\n",
+			itm.ID, itm.Name, itm.Category, itm.Producer, itm.MaxPrice, itm.MinPrice,
+			strings.Join(itm.Sources, ","), itm.UpdatedAt.Format(time.DateTime))
+	}
+	return tw.Flush()
+}
+
+// WriteItemDetail writes a single item as a JSON object, a single line or a table
+func WriteItemDetail(w io.Writer, format Format, detail ItemDetail) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, detail)
+	case FormatNDJSON:
+		return writeNDJSON(w, []ItemDetail{detail})
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID:\t%s\n", detail.ID)
+	fmt.Fprintf(tw, "Name:\t%s\n", detail.Name)
+	fmt.Fprintf(tw, "Category:\t%s\n", detail.Category)
+	fmt.Fprintf(tw, "Producer:\t%s\n", detail.Producer)
+	fmt.Fprintf(tw, "Max price:\t%.2f\n", detail.MaxPrice)
+	fmt.Fprintf(tw, "Min price:\t%.2f\n", detail.MinPrice)
+	fmt.Fprintf(tw, "Sources:\t%s\n", strings.Join(detail.Sources, ","))
+	fmt.Fprintf(tw, "URL:\t%s\n", detail.URL)
+	pageSources := make([]string, 0, len(detail.SourceURLs))
+	for source := range detail.SourceURLs {
+		pageSources = append(pageSources, source)
+	}
+	sort.Strings(pageSources)
+	for _, source := range pageSources {
+		fmt.Fprintf(tw, "URL on %s:\t%s\n", source, detail.SourceURLs[source])
+	}
+	fmt.Fprintf(tw, "Required keywords:\t%s\n", strings.Join(detail.RequiredKeywords, ","))
+	fmt.Fprintf(tw, "Excluded keywords:\t%s\n", strings.Join(detail.ExcludedKeywords, ","))
+	fmt.Fprintf(tw, "Created:\t%s\n", detail.CreatedAt.Format(time.DateTime))
+	fmt.Fprintf(tw, "Updated:\t%s\n", detail.UpdatedAt.Format(time.DateTime))
+
+	for _, latest := range detail.LatestPrices {
+		if latest.Price == nil {
+			fmt.Fprintf(tw, "Price on %s:\tnot scraped yet\n", latest.Source)
+			continue
+		}
+		fmt.Fprintf(tw, "Price on %s:\t%.2f at %s %s\n", latest.Source, *latest.Price,
+			latest.ObservedAt.Format(time.DateTime), latest.URL)
+	}
+
+	return tw.Flush()
+}
+
+// WriteScrapeResults writes the results as a JSON array, one JSON object per line or a table
+func WriteScrapeResults(w io.Writer, format Format, results []ScrapeResult) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, results)
+	case FormatNDJSON:
+		return writeNDJSON(w, results)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ITEM\tSOURCE\tPRICE\tSELLER\tURL\tSTATUS")
+	for _, result := range results {
+		if result.Status != StatusOK {
+			fmt.Fprintf(tw, "%s\t%s\t-\t-\t-\t%s\n", result.Item, result.Source, result.Error)
+			continue
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%.2f\t%s\t%s\t%s\n", result.Item, result.Source,
+			result.Best.Price, result.Best.Seller, result.Best.URL, result.Status)
+		for _, event := range result.Alerts {
+			fmt.Fprintf(tw, "\t\t\t\t\tALERT: %s\n", event.Message)
+		}
+	}
+	return tw.Flush()
+}
+
+// WriteBackups writes the backups as a JSON array, one JSON object per line or a table
+func WriteBackups(w io.Writer, format Format, backups []Backup) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, backups)
+	case FormatNDJSON:
+		return writeNDJSON(w, backups)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tCREATED\tREASON\tSIZE")
+	for _, backup := range backups {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\n", backup.ID,
+			backup.CreatedAt.Local().Format(time.DateTime), backup.Reason, backup.Size)
+	}
+	return tw.Flush()
+}
+
+// WriteImportActions writes the planned changes of an import as a JSON array,
+// one JSON object per line or a table
+func WriteImportActions(w io.Writer, format Format, actions []ImportAction) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, actions)
+	case FormatNDJSON:
+		return writeNDJSON(w, actions)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LINE\tACTION\tNAME\tDETAIL")
+	for _, action := range actions {
+		line := "-"
+		if action.Line > 0 {
+			line = fmt.Sprint(action.Line)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", line, action.Action, action.Name, action.Detail)
+	}
+	return tw.Flush()
+}
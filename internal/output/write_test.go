@@ -0,0 +1,216 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	"github.com/WellyngtonF/WishListCLI/internal/scraper"
+	"github.com/WellyngtonF/WishListCLI/internal/scraper/sources"
+)
+
+var (
+	testCreated = time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	testUpdated = time.Date(2024, 3, 2, 12, 30, 0, 0, time.FixedZone("BRT", -3*60*60))
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Format
+		wantErr bool
+	}{
+		{"json", FormatJSON, false},
+		{" NDJSON ", FormatNDJSON, false},
+		{"Table", FormatTable, false},
+		{"csv", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFormat(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWriteItems(t *testing.T) {
+	items := []Item{
+		NewItem(item.Item{
+			ID:        "1",
+			Name:      "Console",
+			MaxPrice:  3000,
+			CreatedAt: testCreated,
+			UpdatedAt: testUpdated,
+		}),
+		NewItem(item.Item{
+			ID:               "2",
+			Name:             "Controller",
+			Category:         "Games",
+			ScrapingSources:  []string{" Kabum ", ""},
+			RequiredKeywords: []string{"wireless"},
+			SourceURLs:       map[string]string{"Kabum": "https://www.kabum.com.br/produto/1"},
+			CreatedAt:        testCreated,
+			UpdatedAt:        testCreated,
+		}),
+	}
+
+	tests := []struct {
+		format Format
+		items  []Item
+		want   string
+	}{
+		{
+			format: FormatJSON,
+			items:  items[:1],
+			want: `[
+  {
+    "id": "1",
+    "name": "Console",
+    "category": "",
+    "producer": "",
+    "max_price": 3000,
+    "min_price": 0,
+    "sources": [],
+    "url": "",
+    "required_keywords": [],
+    "excluded_keywords": [],
+    "created_at": "2024-03-01T10:00:00Z",
+    "updated_at": "2024-03-02T12:30:00-03:00",
+    "source_urls": {}
+  }
+]
+`,
+		},
+		{
+			format: FormatJSON,
+			items:  []Item{},
+			want:   "[]\n",
+		},
+		{
+			format: FormatNDJSON,
+			items:  items,
+			want: `{"id":"1","name":"Console","category":"","producer":"","max_price":3000,"min_price":0,"sources":[],"url":"","required_keywords":[],"excluded_keywords":[],"created_at":"2024-03-01T10:00:00Z","updated_at":"2024-03-02T12:30:00-03:00","source_urls":{}}
+{"id":"2","name":"Controller","category":"Games","producer":"","max_price":0,"min_price":0,"sources":["Kabum"],"url":"","required_keywords":["wireless"],"excluded_keywords":[],"created_at":"2024-03-01T10:00:00Z","updated_at":"2024-03-01T10:00:00Z","source_urls":{"Kabum":"https://www.kabum.com.br/produto/1"}}
+`,
+		},
+		{
+			format: FormatNDJSON,
+			items:  []Item{},
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := WriteItems(&buf, tt.format, tt.items); err != nil {
+			t.Fatalf("WriteItems(%s) error = %v", tt.format, err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("WriteItems(%s, %d items) =\n%s\nwant\n%s", tt.format, len(tt.items), got, tt.want)
+		}
+	}
+}
+
+func TestWriteItemDetail(t *testing.T) {
+	observed := time.Date(2024, 3, 3, 8, 0, 0, 0, time.UTC)
+	itm := item.Item{
+		ID:              "1",
+		Name:            "Console",
+		ScrapingSources: []string{"Kabum", "Pichau"},
+		CreatedAt:       testCreated,
+		UpdatedAt:       testCreated,
+	}
+	latest := map[string]*item.PriceObservation{
+		"Kabum": {Price: 2899.9, URL: "https://www.kabum.com.br/produto/1", Seller: "KaBuM!", ObservedAt: observed},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteItemDetail(&buf, FormatNDJSON, NewItemDetail(itm, latest)); err != nil {
+		t.Fatalf("WriteItemDetail() error = %v", err)
+	}
+
+	want := `{"id":"1","name":"Console","category":"","producer":"","max_price":0,"min_price":0,"sources":["Kabum","Pichau"],"url":"","required_keywords":[],"excluded_keywords":[],"created_at":"2024-03-01T10:00:00Z","updated_at":"2024-03-01T10:00:00Z","source_urls":{},"latest_prices":[{"source":"Kabum","price":2899.9,"url":"https://www.kabum.com.br/produto/1","seller":"KaBuM!","observed_at":"2024-03-03T08:00:00Z"},{"source":"Pichau","price":null,"url":"","seller":"","observed_at":null}]}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteItemDetail() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteScrapeResults(t *testing.T) {
+	started := time.Date(2024, 3, 3, 8, 0, 0, 0, time.UTC)
+	itm := item.Item{ID: "1", Name: "Console"}
+
+	tests := []struct {
+		name   string
+		result scraper.Result
+		want   string
+	}{
+		{
+			name: "ok",
+			result: scraper.Result{
+				Item:   itm,
+				Source: "Kabum",
+				Scrape: &sources.ScrapeResult{
+					Source: "Kabum",
+					Offers: []sources.Offer{{
+						Title:        "Console",
+						Price:        2899.9,
+						Currency:     "BRL",
+						FreeShipping: true,
+						Availability: sources.AvailabilityInStock,
+						URL:          "https://www.kabum.com.br/produto/1",
+						Score:        1,
+					}},
+					BestIndex: 0,
+				},
+				StartedAt:  started,
+				FinishedAt: started.Add(1500 * time.Millisecond),
+			},
+			want: `{"item_id":"1","item":"Console","source":"Kabum","product_url":"","status":"ok","best":{"title":"Console","price":2899.9,"currency":"BRL","seller":"","shipping_cost":0,"free_shipping":true,"total_price":2899.9,"availability":"in_stock","condition":"","rating":0,"url":"https://www.kabum.com.br/produto/1","score":1,"rejected":""},"offers":[{"title":"Console","price":2899.9,"currency":"BRL","seller":"","shipping_cost":0,"free_shipping":true,"total_price":2899.9,"availability":"in_stock","condition":"","rating":0,"url":"https://www.kabum.com.br/produto/1","score":1,"rejected":""}],"alerts":[],"started_at":"2024-03-03T08:00:00Z","finished_at":"2024-03-03T08:00:01.5Z","duration_ms":1500}
+`,
+		},
+		{
+			name: "error",
+			result: scraper.Result{
+				Item:       itm,
+				Source:     "Pichau",
+				URL:        "https://www.pichau.com.br/console",
+				Err:        errors.New("timeout"),
+				StartedAt:  started,
+				FinishedAt: started,
+			},
+			want: `{"item_id":"1","item":"Console","source":"Pichau","product_url":"https://www.pichau.com.br/console","status":"error","error":"timeout","best":null,"offers":[],"alerts":[],"started_at":"2024-03-03T08:00:00Z","finished_at":"2024-03-03T08:00:00Z","duration_ms":0}
+`,
+		},
+		{
+			name: "no offer",
+			result: scraper.Result{
+				Item:       itm,
+				Source:     "Kabum",
+				Scrape:     &sources.ScrapeResult{Source: "Kabum", BestIndex: -1},
+				StartedAt:  started,
+				FinishedAt: started,
+			},
+			want: `{"item_id":"1","item":"Console","source":"Kabum","product_url":"","status":"error","error":"no offer found","best":null,"offers":[],"alerts":[],"started_at":"2024-03-03T08:00:00Z","finished_at":"2024-03-03T08:00:00Z","duration_ms":0}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := WriteScrapeResults(&buf, FormatNDJSON, []ScrapeResult{NewScrapeResult(tt.result)}); err != nil {
+			t.Fatalf("%s: WriteScrapeResults() error = %v", tt.name, err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("%s: WriteScrapeResults() =\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package persistence
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultBackupDir  = "backups"
+	defaultBackupKeep = 10
+	// backupIDLayout formats the UTC time a snapshot was taken as its ID
+	backupIDLayout = "20060102T150405.000"
+)
+
+// ErrBackupNotFound is returned when no snapshot has the given ID
+var ErrBackupNotFound = errors.New("backup not found")
+
+// Backup is a snapshot of the wishlist taken before a destructive change
+type Backup struct {
+	// ID is the UTC time the snapshot was taken, like 20240501T153000.000
+	ID        string
+	Reason    string
+	CreatedAt time.Time
+	Path      string
+	Size      int64
+}
+
+// Backuper is implemented by stores that keep snapshots of themselves
+type Backuper interface {
+	// Backup takes a snapshot and removes the oldest ones beyond the
+	// retention count. It returns nil when backups are disabled.
+	Backup(reason string) (*Backup, error)
+	// ListBackups returns the snapshots, newest first
+	ListBackups() ([]Backup, error)
+	// RestoreBackup replaces the wishlist with the snapshot with the given
+	// ID or ID prefix. The replaced content is backed up first and its
+	// snapshot returned, nil when backups are disabled.
+	RestoreBackup(id string) (*Backup, error)
+}
+
+// BackupConfig sets where snapshots are kept and how many
+type BackupConfig struct {
+	// Dir is the snapshot directory, relative to the wishlist file directory
+	// unless absolute
+	Dir string
+	// Keep is the number of snapshots kept, zero disables backups
+	Keep int
+}
+
+// BackupConfigFromViper reads the backup section of the config, backups
+// are enabled by default
+func BackupConfigFromViper() BackupConfig {
+	cfg := BackupConfig{
+		Dir:  viper.GetString("backup.dir"),
+		Keep: defaultBackupKeep,
+	}
+	if viper.IsSet("backup.keep") {
+		cfg.Keep = max(viper.GetInt("backup.keep"), 0)
+	}
+	return cfg
+}
+
+// backupDir manages the snapshot files of a wishlist file, named after it
+// with the snapshot ID and reason, like wishlist-20240501T153000.000-update.csv
+type backupDir struct {
+	dir    string
+	prefix string
+	ext    string
+	keep   int
+}
+
+func newBackupDir(filePath string, cfg BackupConfig) *backupDir {
+	dir := cfg.Dir
+	if dir == "" {
+		dir = defaultBackupDir
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(filepath.Dir(filePath), dir)
+	}
+
+	ext := filepath.Ext(filePath)
+	return &backupDir{
+		dir:    dir,
+		prefix: strings.TrimSuffix(filepath.Base(filePath), ext) + "-",
+		ext:    ext,
+		keep:   cfg.Keep,
+	}
+}
+
+func (b *backupDir) enabled() bool {
+	return b.keep > 0
+}
+
+// create takes a snapshot with write, which must create the file at path,
+// and prunes the oldest snapshots
+func (b *backupDir) create(reason string, write func(path string) error) (*Backup, error) {
+	if err := os.MkdirAll(b.dir, 0700); err != nil {
+		return nil, err
+	}
+
+	existing, err := b.list()
+	if err != nil {
+		return nil, err
+	}
+	taken := make(map[string]bool, len(existing))
+	for _, backup := range existing {
+		taken[backup.ID] = true
+	}
+
+	// snapshots taken in the same millisecond, whatever their reason, get
+	// the next free ID
+	now := time.Now().UTC()
+	for taken[now.Format(backupIDLayout)] {
+		now = now.Add(time.Millisecond)
+	}
+	path := filepath.Join(b.dir, b.prefix+now.Format(backupIDLayout)+"-"+reason+b.ext)
+
+	if err := write(path); err != nil {
+		b.remove(path)
+		return nil, fmt.Errorf("error backing up the wishlist: %v", err)
+	}
+
+	if err := b.prune(); err != nil {
+		return nil, fmt.Errorf("error removing old backups: %v", err)
+	}
+
+	backup, ok := b.parse(filepath.Base(path))
+	if !ok {
+		return nil, fmt.Errorf("invalid backup reason %q", reason)
+	}
+	if info, err := os.Stat(path); err == nil {
+		backup.Size = info.Size()
+	}
+	return &backup, nil
+}
+
+// list returns the snapshots newest first
+func (b *backupDir) list() ([]Backup, error) {
+	entries, err := os.ReadDir(b.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []Backup
+	for _, entry := range entries {
+		backup, ok := b.parse(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			backup.Size = info.Size()
+		}
+		backups = append(backups, backup)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].ID > backups[j].ID
+	})
+	return backups, nil
+}
+
+// find returns the snapshot with the ID, or the only one starting with it
+func (b *backupDir) find(id string) (*Backup, error) {
+	backups, err := b.list()
+	if err != nil {
+		return nil, err
+	}
+
+	var found []Backup
+	for _, backup := range backups {
+		if backup.ID == id {
+			return &backup, nil
+		}
+		if strings.HasPrefix(backup.ID, id) {
+			found = append(found, backup)
+		}
+	}
+
+	switch {
+	case id == "" || len(found) == 0:
+		return nil, fmt.Errorf("%w: %s", ErrBackupNotFound, id)
+	case len(found) > 1:
+		return nil, fmt.Errorf("backup ID %s is ambiguous, it matches %d backups", id, len(found))
+	}
+	return &found[0], nil
+}
+
+// prune removes the oldest snapshots beyond the retention count
+func (b *backupDir) prune() error {
+	backups, err := b.list()
+	if err != nil || len(backups) <= b.keep {
+		return err
+	}
+
+	for _, backup := range backups[b.keep:] {
+		if err := b.remove(backup.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remove removes the snapshot at path and the files taken with it, named
+// after it like wishlist-20240501T153000.000-update.history.csv
+func (b *backupDir) remove(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return err
+	}
+	prefix := strings.TrimSuffix(filepath.Base(path), b.ext) + "."
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) || !strings.HasSuffix(entry.Name(), b.ext) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(b.dir, entry.Name())); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// parse reads the ID and reason from a snapshot file name
+func (b *backupDir) parse(name string) (Backup, bool) {
+	if !strings.HasPrefix(name, b.prefix) || !strings.HasSuffix(name, b.ext) {
+		return Backup{}, false
+	}
+
+	id, reason, ok := strings.Cut(strings.TrimSuffix(strings.TrimPrefix(name, b.prefix), b.ext), "-")
+	if !ok || reason == "" || strings.ContainsAny(reason, "-.") {
+		return Backup{}, false
+	}
+
+	createdAt, err := time.Parse(backupIDLayout, id)
+	if err != nil {
+		return Backup{}, false
+	}
+
+	return Backup{
+		ID:        id,
+		Reason:    reason,
+		CreatedAt: createdAt,
+		Path:      filepath.Join(b.dir, name),
+	}, true
+}
+
+// copyFile atomically copies the file at src to dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	return WriteFileAtomic(dst, func(w io.Writer) error {
+		_, err := io.Copy(w, in)
+		return err
+	})
+}
@@ -0,0 +1,208 @@
+package persistence
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+)
+
+func TestBackupIDsAreUniqueAcrossReasons(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "wishlist.csv")
+	backups := newBackupDir(filePath, BackupConfig{Keep: 50})
+
+	ids := make(map[string]string)
+	for i, reason := range []string{"update", "delete", "import", "update", "restore", "delete"} {
+		backup, err := backups.create(reason, func(path string) error {
+			return os.WriteFile(path, []byte{byte(i)}, 0600)
+		})
+		if err != nil {
+			t.Fatalf("create %s: %v", reason, err)
+		}
+		if other, ok := ids[backup.ID]; ok {
+			t.Fatalf("backup %s of %s has the ID of the %s backup", backup.ID, reason, other)
+		}
+		ids[backup.ID] = reason
+
+		if found, err := backups.find(backup.ID); err != nil || found.Reason != reason {
+			t.Errorf("find(%s) = %+v, %v, want the %s backup", backup.ID, found, err, reason)
+		}
+	}
+}
+
+func TestLoadItemsBacksUpBeforeUpgrade(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "wishlist.csv")
+	legacy := "PS5;Games;Sony;3500.00;Amazon;2024-01-01T00:00:00Z;2024-01-01T00:00:00Z;0.00\n"
+	if err := os.WriteFile(filePath, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewCSVStore(filePath).WithBackups(BackupConfig{Keep: 5})
+	if _, err := store.LoadItems(); err != nil {
+		t.Fatalf("LoadItems error: %v", err)
+	}
+
+	backups, err := store.ListBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 || backups[0].Reason != "upgrade" {
+		t.Fatalf("backups = %+v, want one upgrade backup", backups)
+	}
+	data, err := os.ReadFile(backups[0].Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != legacy {
+		t.Errorf("backup content = %q, want the legacy file", data)
+	}
+
+	// the upgraded file is not backed up again
+	if _, err := store.LoadItems(); err != nil {
+		t.Fatal(err)
+	}
+	if backups, _ := store.ListBackups(); len(backups) != 1 {
+		t.Errorf("backups after reload = %d, want 1", len(backups))
+	}
+}
+
+// newCSVStoreWithHistory creates a CSV store snapshotting its price history
+func newCSVStoreWithHistory(t *testing.T, keep int) (*CSVStore, *CSVHistoryStore) {
+	t.Helper()
+
+	dir := t.TempDir()
+	history := NewCSVHistoryStore(filepath.Join(dir, CSVHistoryFile))
+	store := NewCSVStore(filepath.Join(dir, "wishlist.csv")).
+		WithHistory(history).
+		WithBackups(BackupConfig{Keep: keep})
+	return store, history
+}
+
+func observedItems(t *testing.T, history *CSVHistoryStore) []string {
+	t.Helper()
+
+	observations, err := history.LoadObservations(HistoryFilter{})
+	if err != nil {
+		t.Fatalf("LoadObservations error: %v", err)
+	}
+	var names []string
+	for _, obs := range observations {
+		names = append(names, fmt.Sprintf("%s=%.0f", obs.ItemName, obs.Price))
+	}
+	return names
+}
+
+func TestRestoreBackupRestoresHistory(t *testing.T) {
+	store, history := newCSVStoreWithHistory(t, 10)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	console := item.Item{ID: "1", Name: "Console"}
+	if err := store.AddItem(console); err != nil {
+		t.Fatal(err)
+	}
+	if err := history.AddObservation(item.PriceObservation{ItemID: "1", ItemName: "Console", Price: 3000, ObservedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := store.Backup("update")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.AddItem(item.Item{ID: "2", Name: "Desk"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, obs := range []item.PriceObservation{
+		{ItemID: "1", ItemName: "Console", Price: 2800, ObservedAt: now.Add(time.Hour)},
+		{ItemID: "2", ItemName: "Desk", Price: 900, ObservedAt: now.Add(time.Hour)},
+	} {
+		if err := history.AddObservation(obs); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	replaced, err := store.RestoreBackup(snapshot.ID)
+	if err != nil {
+		t.Fatalf("RestoreBackup error: %v", err)
+	}
+	if got, want := observedItems(t, history), []string{"Console=3000"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("history after restore = %v, want %v", got, want)
+	}
+
+	// restoring the replaced snapshot brings back the newer history
+	if _, err := store.RestoreBackup(replaced.ID); err != nil {
+		t.Fatalf("RestoreBackup of the replaced wishlist error: %v", err)
+	}
+	if got, want := observedItems(t, history), []string{"Console=3000", "Console=2800", "Desk=900"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("history after undoing the restore = %v, want %v", got, want)
+	}
+	items, err := store.LoadItems()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Errorf("items after undoing the restore = %+v, want Console and Desk", items)
+	}
+}
+
+func TestRestoreBackupWithoutHistoryDropsOrphans(t *testing.T) {
+	store, history := newCSVStoreWithHistory(t, 10)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// a snapshot taken before snapshots included the history
+	var data bytes.Buffer
+	if err := WriteItemsCSV(&data, []item.Item{{ID: "1", Name: "Console"}}); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := store.backups.create("update", func(path string) error {
+		return os.WriteFile(path, data.Bytes(), 0600)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, obs := range []item.PriceObservation{
+		{ItemID: "1", ItemName: "Console", Price: 3000, ObservedAt: now},
+		{ItemID: "2", ItemName: "Desk", Price: 900, ObservedAt: now},
+		{ItemName: "Console", Price: 3100, ObservedAt: now.Add(time.Hour)},
+	} {
+		if err := history.AddObservation(obs); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := store.RestoreBackup(snapshot.ID); err != nil {
+		t.Fatalf("RestoreBackup error: %v", err)
+	}
+	if got, want := observedItems(t, history), []string{"Console=3000", "Console=3100"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("history after restore = %v, want %v", got, want)
+	}
+}
+
+func TestPruneRemovesHistorySnapshots(t *testing.T) {
+	store, _ := newCSVStoreWithHistory(t, 1)
+
+	for _, reason := range []string{"update", "delete", "import"} {
+		if _, err := store.Backup(reason); err != nil {
+			t.Fatalf("Backup(%s) error: %v", reason, err)
+		}
+	}
+
+	entries, err := os.ReadDir(store.backups.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	// the snapshot sorts before its history
+	if len(names) != 2 || !strings.HasSuffix(names[0], "-import.csv") || names[1] != strings.TrimSuffix(names[0], ".csv")+".history.csv" {
+		t.Errorf("backup files = %v, want the newest snapshot and its history", names)
+	}
+}
@@ -1,9 +1,14 @@
 package persistence
 
 import (
+	"bytes"
 	"encoding/csv"
 	"errors"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -11,6 +16,41 @@ import (
 	"github.com/WellyngtonF/WishListCLI/internal/item"
 )
 
+// csvSchemaVersion is written in the first line of the file and must be
+// increased whenever csvColumns changes
+const csvSchemaVersion = 4
+
+const schemaPrefix = "#schema:"
+
+// csvColumns is the header written to the CSV file, one column per item.Item field
+var csvColumns = []string{
+	"ID",
+	"Name",
+	"Category",
+	"Producer",
+	"MaxPrice",
+	"ScrapingSources",
+	"URL",
+	"CreatedAt",
+	"UpdatedAt",
+	"MinPrice",
+	"RequiredKeywords",
+	"ExcludedKeywords",
+	"SourceURLs",
+}
+
+// legacyColumns is the positional layout of files written before the header row
+var legacyColumns = []string{
+	"Name",
+	"Category",
+	"Producer",
+	"MaxPrice",
+	"ScrapingSources",
+	"CreatedAt",
+	"UpdatedAt",
+	"MinPrice",
+}
+
 // Helper function to parse float from string
 func parseFloat(value string) (float64, error) {
 	return strconv.ParseFloat(value, 64)
@@ -21,6 +61,42 @@ func formatFloat(value float64) string {
 	return strconv.FormatFloat(value, 'f', 2, 64)
 }
 
+// Helper function to split a comma separated list, empty values give nil
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// ParseSourceURLs parses source URLs written by FormatSourceURLs, empty values give nil
+func ParseSourceURLs(value string) map[string]string {
+	var urls map[string]string
+	for _, entry := range strings.Split(value, "|") {
+		source, url, ok := strings.Cut(entry, "=")
+		source, url = strings.TrimSpace(source), strings.TrimSpace(url)
+		if !ok || source == "" || url == "" {
+			continue
+		}
+		if urls == nil {
+			urls = make(map[string]string)
+		}
+		urls[source] = url
+	}
+	return urls
+}
+
+// FormatSourceURLs formats source URLs like "Amazon=https://...|Mercado Livre=https://...",
+// sorted by source. URLs cannot contain a raw "|".
+func FormatSourceURLs(urls map[string]string) string {
+	entries := make([]string, 0, len(urls))
+	for source, url := range urls {
+		entries = append(entries, source+"="+url)
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, "|")
+}
+
 // Helper function to parse time from string
 func parseTime(value string) (time.Time, error) {
 	return time.Parse(time.RFC3339, value)
@@ -31,6 +107,148 @@ func formatTime(t time.Time) string {
 	return t.Format(time.RFC3339)
 }
 
+// CSVStore stores the items in a CSV file. Every change locks the file and
+// rewrites it atomically, so other processes never see it half written.
+type CSVStore struct {
+	filePath string
+	lock     *fileLock
+	backups  *backupDir
+	// history is snapshotted and restored along with the items, nil when
+	// WithHistory was not called
+	history *CSVHistoryStore
+}
+
+// NewCSVStore creates a store backed by the CSV file at filePath, backups
+// are disabled until WithBackups is called
+func NewCSVStore(filePath string) *CSVStore {
+	return &CSVStore{
+		filePath: filePath,
+		lock:     &fileLock{filePath: filePath},
+		backups:  newBackupDir(filePath, BackupConfig{}),
+	}
+}
+
+// WithBackups sets where snapshots of the CSV file are kept and how many
+func (s *CSVStore) WithBackups(cfg BackupConfig) *CSVStore {
+	s.backups = newBackupDir(s.filePath, cfg)
+	return s
+}
+
+// WithHistory snapshots and restores the price history file along with the
+// CSV file, so a restored wishlist keeps the history of its own items
+func (s *CSVStore) WithHistory(history *CSVHistoryStore) *CSVStore {
+	s.history = history
+	return s
+}
+
+// Backup copies the CSV file to a new snapshot
+func (s *CSVStore) Backup(reason string) (*Backup, error) {
+	var backup *Backup
+	err := s.lock.do(func() error {
+		var err error
+		backup, err = s.backup(reason)
+		return err
+	})
+	return backup, err
+}
+
+// ListBackups returns the snapshots of the CSV file, newest first
+func (s *CSVStore) ListBackups() ([]Backup, error) {
+	return s.backups.list()
+}
+
+// RestoreBackup replaces the CSV file and the price history with the
+// snapshot. Snapshots taken without the history keep the observations of
+// the restored items only.
+func (s *CSVStore) RestoreBackup(id string) (*Backup, error) {
+	backup, err := s.backups.find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var replaced *Backup
+	err = s.lock.do(func() error {
+		// read before backing up, the snapshot may be pruned
+		data, err := os.ReadFile(backup.Path)
+		if err != nil {
+			return err
+		}
+		f, err := parseCSV(data, csvColumns, csvSchemaVersion)
+		if err != nil {
+			return fmt.Errorf("backup %s: %v", backup.ID, err)
+		}
+		withLegacyHeader(f)
+		items, err := decodeItems(f)
+		if err != nil {
+			return fmt.Errorf("backup %s: %v", backup.ID, err)
+		}
+
+		var history []byte
+		if s.history != nil {
+			history, err = os.ReadFile(historySnapshotPath(backup.Path))
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			if history != nil {
+				if _, err := parseCSV(history, historyColumns, historySchemaVersion); err != nil {
+					return fmt.Errorf("backup %s: price history: %v", backup.ID, err)
+				}
+			}
+		}
+
+		if replaced, err = s.backup("restore"); err != nil {
+			return err
+		}
+
+		err = WriteFileAtomic(s.filePath, func(w io.Writer) error {
+			_, err := w.Write(data)
+			return err
+		})
+		if err != nil || s.history == nil {
+			return err
+		}
+
+		if history == nil {
+			err = s.history.keepItems(items)
+		} else {
+			err = s.history.restore(history)
+		}
+		if err != nil {
+			return fmt.Errorf("the wishlist was restored but not its price history: %v", err)
+		}
+		return nil
+	})
+	return replaced, err
+}
+
+// backup copies the CSV file and the price history to a new snapshot. The
+// caller holds the lock.
+func (s *CSVStore) backup(reason string) (*Backup, error) {
+	if !s.backups.enabled() {
+		return nil, nil
+	}
+	if err := CreateFile(s.filePath); err != nil {
+		return nil, err
+	}
+
+	return s.backups.create(reason, func(path string) error {
+		if err := copyFile(s.filePath, path); err != nil {
+			return err
+		}
+		if s.history == nil {
+			return nil
+		}
+		return s.history.snapshot(historySnapshotPath(path))
+	})
+}
+
+// historySnapshotPath returns the path of the price history taken with the
+// snapshot at path, like wishlist-20240501T153000.000-update.history.csv
+func historySnapshotPath(path string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + ".history" + ext
+}
+
 func CreateFile(filePath string) error {
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		file, err := os.Create(filePath)
@@ -43,155 +261,391 @@ func CreateFile(filePath string) error {
 	return nil
 }
 
-// LoadItems loads the CSV file and returns all items
-func LoadItems(filePath string) ([]item.Item, error) {
-	CreateFile(filePath)
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, err
+// csvFile is the parsed content of the CSV file
+type csvFile struct {
+	version int
+	header  []string
+	records [][]string
+}
+
+// needsUpgrade reports whether the file must be rewritten in the current format,
+// either because it is older than version or because its header lacks one of columns
+func (f *csvFile) needsUpgrade(columns []string, version int) bool {
+	if len(f.records) == 0 {
+		return false
+	}
+	if f.version < version {
+		return true
 	}
-	defer file.Close()
 
-	reader := csv.NewReader(file)
-	reader.Comma = ';' // Use semicolon as column separator
-	records, err := reader.ReadAll()
-	if err != nil {
-		return nil, err
+	present := make(map[string]bool, len(f.header))
+	for _, name := range f.header {
+		present[strings.ToLower(strings.TrimSpace(name))] = true
 	}
+	for _, name := range columns {
+		if !present[strings.ToLower(name)] {
+			return true
+		}
+	}
+	return false
+}
 
+// LoadItems loads the CSV file and returns all items. Files written by an
+// older version are rewritten in the current format and items without an ID
+// get one.
+func (s *CSVStore) LoadItems() ([]item.Item, error) {
 	var items []item.Item
-	for _, record := range records {
-		maxPrice, err := parseFloat(record[3])
+	err := s.lock.do(func() error {
+		var err error
+		items, err = s.loadItems()
+		return err
+	})
+	return items, err
+}
+
+// AddItem adds a new item at the end of the CSV file
+func (s *CSVStore) AddItem(newItem item.Item) error {
+	return s.lock.do(func() error {
+		items, err := s.loadItems()
 		if err != nil {
-			return nil, err
+			return err
 		}
+		return s.saveItems(append(items, newItem))
+	})
+}
 
-		createdAt, err := parseTime(record[5])
+// UpdateItem updates the item with the same ID in the CSV file
+func (s *CSVStore) UpdateItem(updatedItem item.Item) error {
+	return s.lock.do(func() error {
+		items, err := s.loadItems()
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		updatedAt, err := parseTime(record[6])
-		if err != nil {
-			return nil, err
+		found := false
+		for i, itm := range items {
+			if itm.ID == updatedItem.ID {
+				items[i] = updatedItem
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return errors.New("item not found")
 		}
 
-		minPrice, err := parseFloat(record[7])
+		return s.saveItems(items)
+	})
+}
+
+// DeleteItem deletes the item with the given ID from the CSV file
+func (s *CSVStore) DeleteItem(id string) error {
+	return s.lock.do(func() error {
+		items, err := s.loadItems()
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		items = append(items, item.Item{
-			Name:            record[0],
-			Category:        record[1],
-			Producer:        record[2],
-			MaxPrice:        maxPrice,
-			ScrapingSources: strings.Split(record[4], ","),
-			CreatedAt:       createdAt,
-			UpdatedAt:       updatedAt,
-			MinPrice:        minPrice,
-		})
+		var updatedItems []item.Item
+		for _, itm := range items {
+			if itm.ID != id {
+				updatedItems = append(updatedItems, itm)
+			}
+		}
+
+		return s.saveItems(updatedItems)
+	})
+}
+
+// loadItems reads the items, upgrading the file when needed. The caller
+// holds the lock.
+func (s *CSVStore) loadItems() ([]item.Item, error) {
+	f, err := s.readFile()
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := decodeItems(f)
+	if err != nil {
+		return nil, err
+	}
+
+	if assignIDs(items) || f.needsUpgrade(csvColumns, csvSchemaVersion) {
+		// the file written by the older version is kept until it is pruned
+		if _, err := s.backup("upgrade"); err != nil {
+			return nil, err
+		}
+		if err := s.saveItems(items); err != nil {
+			return nil, fmt.Errorf("error upgrading %s: %v", s.filePath, err)
+		}
 	}
 
 	return items, nil
 }
 
-// AddItem appends a new item to the CSV file
-func AddItem(filePath string, newItem item.Item) error {
-	CreateFile(filePath)
-	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
+// assignIDs gives an ID to the items without one and reports whether any was assigned
+func assignIDs(items []item.Item) bool {
+	assigned := false
+	for i := range items {
+		if items[i].ID == "" {
+			items[i].ID = item.NewID()
+			assigned = true
+		}
+	}
+	return assigned
+}
+
+// saveItems atomically replaces the CSV file with the items
+func (s *CSVStore) saveItems(items []item.Item) error {
+	return WriteFileAtomic(s.filePath, func(w io.Writer) error {
+		return WriteItemsCSV(w, items)
+	})
+}
+
+// readFile reads the schema version, header and records of the CSV file.
+// Headerless files are returned with the legacy positional header and version 0.
+func (s *CSVStore) readFile() (*csvFile, error) {
+	f, err := readCSVFile(s.filePath, csvColumns, csvSchemaVersion)
 	if err != nil {
+		return nil, err
+	}
+
+	withLegacyHeader(f)
+	return f, nil
+}
+
+// withLegacyHeader sets the legacy positional header on headerless files
+func withLegacyHeader(f *csvFile) {
+	if f.header == nil && len(f.records) > 0 {
+		f.header = legacyColumns
+		f.version = 0
+	}
+}
+
+// WriteItemsCSV writes the items in the wishlist CSV format, with schema version and header
+func WriteItemsCSV(w io.Writer, items []item.Item) error {
+	if err := writeHeader(w, csvSchemaVersion, csvColumns); err != nil {
 		return err
 	}
-	defer file.Close()
 
-	writer := csv.NewWriter(file)
+	writer := csv.NewWriter(w)
 	writer.Comma = ';'
 
-	err = writer.Write([]string{
-		newItem.Name,
-		newItem.Category,
-		newItem.Producer,
-		formatFloat(newItem.MaxPrice),
-		strings.Join(newItem.ScrapingSources, ","),
-		formatTime(newItem.CreatedAt),
-		formatTime(newItem.UpdatedAt),
-		formatFloat(newItem.MinPrice),
-	})
-	if err != nil {
-		return err
+	for _, itm := range items {
+		if err := writer.Write(encodeItem(csvColumns, itm)); err != nil {
+			return err
+		}
 	}
 
 	writer.Flush()
 	return writer.Error()
 }
 
-// UpdateItem updates an existing item in the CSV file
-func UpdateItem(filePath string, updatedItem item.Item) error {
-	items, err := LoadItems(filePath)
+// ReadItemsCSV reads items in the wishlist CSV format, including headerless legacy files
+func ReadItemsCSV(r io.Reader) ([]item.Item, error) {
+	data, err := io.ReadAll(r)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	found := false
-	for i, itm := range items {
-		if itm.Name == updatedItem.Name {
-			items[i] = updatedItem
-			found = true
-			break
-		}
+	f, err := parseCSV(data, csvColumns, csvSchemaVersion)
+	if err != nil {
+		return nil, err
 	}
 
-	if !found {
-		return errors.New("item not found")
+	withLegacyHeader(f)
+	return decodeItems(f)
+}
+
+// readCSVFile reads a semicolon separated file whose first line may hold the
+// schema version. The first record is the header after the schema line, or
+// when every cell of it is one of columns.
+func readCSVFile(filePath string, columns []string, maxVersion int) (*csvFile, error) {
+	CreateFile(filePath)
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
 	}
 
-	return saveItems(filePath, items)
+	f, err := parseCSV(data, columns, maxVersion)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filePath, err)
+	}
+	return f, nil
 }
 
-// DeleteItem deletes an item from the CSV file
-func DeleteItem(filePath string, name string) error {
-	items, err := LoadItems(filePath)
+// parseCSV parses the content of a file read by readCSVFile
+func parseCSV(data []byte, columns []string, maxVersion int) (*csvFile, error) {
+	var err error
+	f := &csvFile{}
+
+	firstLine, rest, _ := bytes.Cut(data, []byte("\n"))
+	version, hasSchema := strings.CutPrefix(strings.TrimSpace(string(firstLine)), schemaPrefix)
+	if hasSchema {
+		data = rest
+		if f.version, err = strconv.Atoi(version); err != nil {
+			return nil, fmt.Errorf("invalid schema version %q", version)
+		}
+		if f.version > maxVersion {
+			return nil, fmt.Errorf("schema version %d is newer than the supported version %d", f.version, maxVersion)
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = ';' // Use semicolon as column separator
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	var updatedItems []item.Item
-	for _, itm := range items {
-		if itm.Name != name {
-			updatedItems = append(updatedItems, itm)
+	// the schema line is always followed by the header, legacy files only
+	// have one when every cell names a column
+	if len(records) > 0 && (hasSchema || isHeader(records[0], columns)) {
+		f.header = records[0]
+		records = records[1:]
+	}
+	f.records = records
+
+	return f, nil
+}
+
+// isHeader reports whether every cell of record is one of columns, ignoring case
+func isHeader(record []string, columns []string) bool {
+	for _, name := range record {
+		if !isColumn(name, columns) {
+			return false
 		}
 	}
+	return true
+}
 
-	return saveItems(filePath, updatedItems)
+// isColumn reports whether name is one of columns, ignoring case
+func isColumn(name string, columns []string) bool {
+	for _, column := range columns {
+		if strings.EqualFold(strings.TrimSpace(name), column) {
+			return true
+		}
+	}
+	return false
 }
 
-// Helper function to save items to the CSV file
-func saveItems(filePath string, items []item.Item) error {
-	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
-	if err != nil {
+func writeHeader(w io.Writer, version int, columns []string) error {
+	if _, err := fmt.Fprintf(w, "%s%d\n", schemaPrefix, version); err != nil {
 		return err
 	}
-	defer file.Close()
 
-	writer := csv.NewWriter(file)
+	writer := csv.NewWriter(w)
 	writer.Comma = ';'
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
 
-	for _, itm := range items {
-		err = writer.Write([]string{
-			itm.Name,
-			itm.Category,
-			itm.Producer,
-			formatFloat(itm.MaxPrice),
-			strings.Join(itm.ScrapingSources, ","),
-			formatTime(itm.CreatedAt),
-			formatTime(itm.UpdatedAt),
-		})
+// columnGetter returns a function reading a record field by column name
+func columnGetter(header []string) func(record []string, column string) string {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	return func(record []string, column string) string {
+		i, ok := index[strings.ToLower(column)]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+}
+
+// decodeItems converts the records to items reading each field by column name.
+// Missing columns and empty values are left as zero values, unknown columns are ignored.
+func decodeItems(f *csvFile) ([]item.Item, error) {
+	column := columnGetter(f.header)
+
+	var items []item.Item
+	for line, record := range f.records {
+		get := func(name string) string {
+			return column(record, name)
+		}
+
+		itm, err := decodeItem(get)
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("record %d: %v", line+1, err)
 		}
+		items = append(items, itm)
 	}
 
-	writer.Flush()
-	return writer.Error()
+	return items, nil
+}
+
+func decodeItem(get func(column string) string) (item.Item, error) {
+	var err error
+	itm := item.Item{
+		ID:       get("ID"),
+		Name:     get("Name"),
+		Category: get("Category"),
+		Producer: get("Producer"),
+		URL:      get("URL"),
+	}
+
+	itm.ScrapingSources = splitList(get("ScrapingSources"))
+	itm.RequiredKeywords = splitList(get("RequiredKeywords"))
+	itm.ExcludedKeywords = splitList(get("ExcludedKeywords"))
+	itm.SourceURLs = ParseSourceURLs(get("SourceURLs"))
+
+	if value := get("MaxPrice"); value != "" {
+		if itm.MaxPrice, err = parseFloat(value); err != nil {
+			return itm, fmt.Errorf("invalid MaxPrice: %v", err)
+		}
+	}
+
+	if value := get("MinPrice"); value != "" {
+		if itm.MinPrice, err = parseFloat(value); err != nil {
+			return itm, fmt.Errorf("invalid MinPrice: %v", err)
+		}
+	}
+
+	if value := get("CreatedAt"); value != "" {
+		if itm.CreatedAt, err = parseTime(value); err != nil {
+			return itm, fmt.Errorf("invalid CreatedAt: %v", err)
+		}
+	}
+
+	if value := get("UpdatedAt"); value != "" {
+		if itm.UpdatedAt, err = parseTime(value); err != nil {
+			return itm, fmt.Errorf("invalid UpdatedAt: %v", err)
+		}
+	}
+
+	return itm, nil
+}
+
+// encodeItem returns the item fields in the order of header
+func encodeItem(header []string, itm item.Item) []string {
+	values := map[string]string{
+		"id":               itm.ID,
+		"name":             itm.Name,
+		"category":         itm.Category,
+		"producer":         itm.Producer,
+		"maxprice":         formatFloat(itm.MaxPrice),
+		"scrapingsources":  strings.Join(itm.ScrapingSources, ","),
+		"url":              itm.URL,
+		"createdat":        formatTime(itm.CreatedAt),
+		"updatedat":        formatTime(itm.UpdatedAt),
+		"minprice":         formatFloat(itm.MinPrice),
+		"requiredkeywords": strings.Join(itm.RequiredKeywords, ","),
+		"excludedkeywords": strings.Join(itm.ExcludedKeywords, ","),
+		"sourceurls":       FormatSourceURLs(itm.SourceURLs),
+	}
+
+	record := make([]string, len(header))
+	for i, column := range header {
+		record[i] = values[strings.ToLower(strings.TrimSpace(column))]
+	}
+	return record
 }
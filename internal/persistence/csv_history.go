@@ -0,0 +1,278 @@
+package persistence
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+)
+
+// CSVHistoryFile is the name of the price history file kept next to a CSV wishlist
+const CSVHistoryFile = "price_history.csv"
+
+// historySchemaVersion is the schema version of the price history CSV file
+const historySchemaVersion = 2
+
+// historyColumns is the header of the price history CSV file
+var historyColumns = []string{
+	"ItemID",
+	"ItemName",
+	"Source",
+	"Price",
+	"URL",
+	"Seller",
+	"ObservedAt",
+}
+
+// CSVHistoryStore stores price observations in an append-only CSV file.
+// Appends and rewrites lock the file, rewrites are atomic.
+type CSVHistoryStore struct {
+	filePath string
+	lock     *fileLock
+}
+
+// NewCSVHistoryStore creates a history store backed by the CSV file at filePath
+func NewCSVHistoryStore(filePath string) *CSVHistoryStore {
+	return &CSVHistoryStore{filePath: filePath, lock: &fileLock{filePath: filePath}}
+}
+
+// AddObservation appends a price observation to the CSV file. Files written
+// by an older version are rewritten in the current format first.
+func (s *CSVHistoryStore) AddObservation(obs item.PriceObservation) error {
+	return s.lock.do(func() error {
+		return s.addObservation(obs)
+	})
+}
+
+// addObservation appends the observation and syncs the file. The caller
+// holds the lock.
+func (s *CSVHistoryStore) addObservation(obs item.PriceObservation) error {
+	f, err := readCSVFile(s.filePath, historyColumns, historySchemaVersion)
+	if err != nil {
+		return err
+	}
+
+	if f.needsUpgrade(historyColumns, historySchemaVersion) {
+		observations, err := decodeObservations(f)
+		if err != nil {
+			return err
+		}
+		return s.saveObservations(append(observations, obs))
+	}
+
+	file, err := os.OpenFile(s.filePath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	header := f.header
+	if header == nil {
+		header = historyColumns
+		if err := writeHeader(file, historySchemaVersion, historyColumns); err != nil {
+			return err
+		}
+	}
+
+	writer := csv.NewWriter(file)
+	writer.Comma = ';'
+
+	if err := writer.Write(encodeObservation(header, obs)); err != nil {
+		return err
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// LoadObservations returns the observations matching the filter ordered by time
+func (s *CSVHistoryStore) LoadObservations(filter HistoryFilter) ([]item.PriceObservation, error) {
+	f, err := readCSVFile(s.filePath, historyColumns, historySchemaVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := decodeObservations(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var observations []item.PriceObservation
+	for _, obs := range all {
+		if filter.Match(obs) {
+			observations = append(observations, obs)
+		}
+	}
+
+	sort.SliceStable(observations, func(i, j int) bool {
+		return observations[i].ObservedAt.Before(observations[j].ObservedAt)
+	})
+
+	return observations, nil
+}
+
+// AssignItemID sets the item ID of the observations of itemName without one
+func (s *CSVHistoryStore) AssignItemID(itemName, itemID string) error {
+	return s.lock.do(func() error {
+		f, err := readCSVFile(s.filePath, historyColumns, historySchemaVersion)
+		if err != nil {
+			return err
+		}
+
+		observations, err := decodeObservations(f)
+		if err != nil {
+			return err
+		}
+
+		assigned := false
+		for i, obs := range observations {
+			if obs.ItemID == "" && obs.ItemName == itemName {
+				observations[i].ItemID = itemID
+				assigned = true
+			}
+		}
+
+		if !assigned {
+			return nil
+		}
+		return s.saveObservations(observations)
+	})
+}
+
+// snapshot copies the CSV file to path, writing an empty history when there
+// is no file yet
+func (s *CSVHistoryStore) snapshot(path string) error {
+	return s.lock.do(func() error {
+		if _, err := os.Stat(s.filePath); os.IsNotExist(err) {
+			return WriteFileAtomic(path, func(w io.Writer) error {
+				return writeHeader(w, historySchemaVersion, historyColumns)
+			})
+		}
+		return copyFile(s.filePath, path)
+	})
+}
+
+// restore atomically replaces the CSV file with the content of a snapshot
+func (s *CSVHistoryStore) restore(data []byte) error {
+	return s.lock.do(func() error {
+		return WriteFileAtomic(s.filePath, func(w io.Writer) error {
+			_, err := w.Write(data)
+			return err
+		})
+	})
+}
+
+// keepItems removes the observations of items not in items. Observations
+// without an item ID are matched by item name.
+func (s *CSVHistoryStore) keepItems(items []item.Item) error {
+	ids := make(map[string]bool, len(items))
+	names := make(map[string]bool, len(items))
+	for _, itm := range items {
+		ids[itm.ID] = true
+		names[itm.Name] = true
+	}
+
+	return s.lock.do(func() error {
+		f, err := readCSVFile(s.filePath, historyColumns, historySchemaVersion)
+		if err != nil {
+			return err
+		}
+
+		observations, err := decodeObservations(f)
+		if err != nil {
+			return err
+		}
+
+		kept := observations[:0]
+		for _, obs := range observations {
+			if (obs.ItemID != "" && ids[obs.ItemID]) || (obs.ItemID == "" && names[obs.ItemName]) {
+				kept = append(kept, obs)
+			}
+		}
+
+		if len(kept) == len(observations) {
+			return nil
+		}
+		return s.saveObservations(kept)
+	})
+}
+
+// saveObservations atomically replaces the CSV file with the observations
+func (s *CSVHistoryStore) saveObservations(observations []item.PriceObservation) error {
+	return WriteFileAtomic(s.filePath, func(w io.Writer) error {
+		if err := writeHeader(w, historySchemaVersion, historyColumns); err != nil {
+			return err
+		}
+
+		writer := csv.NewWriter(w)
+		writer.Comma = ';'
+
+		for _, obs := range observations {
+			if err := writer.Write(encodeObservation(historyColumns, obs)); err != nil {
+				return err
+			}
+		}
+
+		writer.Flush()
+		return writer.Error()
+	})
+}
+
+// decodeObservations converts the records to observations reading each
+// field by column name, headerless files use the current columns
+func decodeObservations(f *csvFile) ([]item.PriceObservation, error) {
+	header := f.header
+	if header == nil {
+		header = historyColumns
+	}
+	column := columnGetter(header)
+
+	var err error
+	var observations []item.PriceObservation
+	for line, record := range f.records {
+		obs := item.PriceObservation{
+			ItemID:   column(record, "ItemID"),
+			ItemName: column(record, "ItemName"),
+			Source:   column(record, "Source"),
+			URL:      column(record, "URL"),
+			Seller:   column(record, "Seller"),
+		}
+
+		if obs.Price, err = parseFloat(column(record, "Price")); err != nil {
+			return nil, fmt.Errorf("record %d: invalid Price: %v", line+1, err)
+		}
+		if obs.ObservedAt, err = parseTime(column(record, "ObservedAt")); err != nil {
+			return nil, fmt.Errorf("record %d: invalid ObservedAt: %v", line+1, err)
+		}
+
+		observations = append(observations, obs)
+	}
+
+	return observations, nil
+}
+
+// encodeObservation returns the observation fields in the order of header
+func encodeObservation(header []string, obs item.PriceObservation) []string {
+	values := map[string]string{
+		"itemid":     obs.ItemID,
+		"itemname":   obs.ItemName,
+		"source":     obs.Source,
+		"price":      formatFloat(obs.Price),
+		"url":        obs.URL,
+		"seller":     obs.Seller,
+		"observedat": formatTime(obs.ObservedAt),
+	}
+
+	record := make([]string, len(header))
+	for i, column := range header {
+		record[i] = values[strings.ToLower(strings.TrimSpace(column))]
+	}
+	return record
+}
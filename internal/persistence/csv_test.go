@@ -0,0 +1,164 @@
+package persistence
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantVersion int
+		wantHeader  []string
+		wantRecords int
+	}{
+		{
+			name:        "schema and header",
+			data:        "#schema:4\nID;Name;MaxPrice\n1;PS5;3500.00\n",
+			wantVersion: 4,
+			wantHeader:  []string{"ID", "Name", "MaxPrice"},
+			wantRecords: 1,
+		},
+		{
+			name:        "header without schema",
+			data:        "Name;Category;MaxPrice\nPS5;Games;3500.00\n",
+			wantHeader:  []string{"Name", "Category", "MaxPrice"},
+			wantRecords: 1,
+		},
+		{
+			name:        "legacy record starting with a column name",
+			data:        "Name;Games;Sony;3500.00;Amazon;2024-01-01T00:00:00Z;2024-01-01T00:00:00Z;0.00\n",
+			wantRecords: 1,
+		},
+		{
+			name:        "legacy records",
+			data:        "PS5;Games;Sony;3500.00;Amazon;;;0.00\nXbox;Games;Microsoft;3000.00;;;;\n",
+			wantRecords: 2,
+		},
+		{
+			name:        "empty file",
+			data:        "",
+			wantRecords: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := parseCSV([]byte(tt.data), csvColumns, csvSchemaVersion)
+			if err != nil {
+				t.Fatalf("parseCSV error: %v", err)
+			}
+			if f.version != tt.wantVersion {
+				t.Errorf("version = %d, want %d", f.version, tt.wantVersion)
+			}
+			if !reflect.DeepEqual(f.header, tt.wantHeader) {
+				t.Errorf("header = %q, want %q", f.header, tt.wantHeader)
+			}
+			if len(f.records) != tt.wantRecords {
+				t.Errorf("records = %d, want %d", len(f.records), tt.wantRecords)
+			}
+		})
+	}
+}
+
+func TestParseCSVSchemaErrors(t *testing.T) {
+	for _, data := range []string{"#schema:x\nID;Name\n", "#schema:99\nID;Name\n"} {
+		if _, err := parseCSV([]byte(data), csvColumns, csvSchemaVersion); err == nil {
+			t.Errorf("parseCSV(%q) expected an error", data)
+		}
+	}
+}
+
+func TestReadItemsCSV(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		wantNames []string
+		wantMax   float64
+	}{
+		{
+			name:      "legacy",
+			data:      "PS5;Games;Sony;3500.00;Amazon,Kabum;2024-01-01T00:00:00Z;2024-01-01T00:00:00Z;2000.00\n",
+			wantNames: []string{"PS5"},
+			wantMax:   3500,
+		},
+		{
+			name:      "legacy first item named Name",
+			data:      "Name;Games;Sony;3500.00;Amazon;2024-01-01T00:00:00Z;2024-01-01T00:00:00Z;0.00\n",
+			wantNames: []string{"Name"},
+			wantMax:   3500,
+		},
+		{
+			name:      "columns in another order",
+			data:      "#schema:4\nMaxPrice;Name;ID\n3500.00;PS5;abc\n",
+			wantNames: []string{"PS5"},
+			wantMax:   3500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := ReadItemsCSV(strings.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("ReadItemsCSV error: %v", err)
+			}
+
+			var names []string
+			for _, itm := range items {
+				names = append(names, itm.Name)
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Fatalf("names = %q, want %q", names, tt.wantNames)
+			}
+			if items[0].MaxPrice != tt.wantMax {
+				t.Errorf("MaxPrice = %v, want %v", items[0].MaxPrice, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestLoadItemsUpgradesLegacyFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "wishlist.csv")
+	legacy := "Name;Games;Sony;3500.00;Amazon;2024-01-01T00:00:00Z;2024-01-01T00:00:00Z;2000.00\n" +
+		"Xbox;Games;Microsoft;3000.00;;2024-01-01T00:00:00Z;2024-01-01T00:00:00Z;0.00\n"
+	if err := os.WriteFile(filePath, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := NewCSVStore(filePath).LoadItems()
+	if err != nil {
+		t.Fatalf("LoadItems error: %v", err)
+	}
+	if len(items) != 2 || items[0].Name != "Name" || items[0].MinPrice != 2000 || items[1].Name != "Xbox" {
+		t.Fatalf("items = %+v", items)
+	}
+	for _, itm := range items {
+		if itm.ID == "" {
+			t.Errorf("%s has no ID", itm.Name)
+		}
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := parseCSV(data, csvColumns, csvSchemaVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.version != csvSchemaVersion || !reflect.DeepEqual(f.header, csvColumns) || len(f.records) != 2 {
+		t.Errorf("upgraded file:\n%s", data)
+	}
+
+	reloaded, err := NewCSVStore(filePath).LoadItems()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reloaded, items) {
+		t.Errorf("reloaded items = %+v, want %+v", reloaded, items)
+	}
+}
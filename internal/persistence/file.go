@@ -0,0 +1,101 @@
+package persistence
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrLocked is returned when another process keeps a CSV file locked for
+// longer than lockTimeout
+var ErrLocked = errors.New("file is locked by another process")
+
+const (
+	// lockTimeout is how long to wait for another process to release a file
+	lockTimeout = 3 * time.Second
+	// lockRetry is the interval between attempts to take a lock
+	lockRetry = 50 * time.Millisecond
+)
+
+// fileLock is an advisory lock on a CSV file. The lock is taken on a
+// separate .lock file because atomic writes replace the data file.
+type fileLock struct {
+	mu       sync.Mutex
+	filePath string
+}
+
+// do runs fn holding the lock, other goroutines and processes using the same
+// file wait for it
+func (l *fileLock) do(fn func() error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.OpenFile(l.filePath+".lock", os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		locked, err := tryLock(file)
+		if err != nil {
+			return fmt.Errorf("error locking %s: %v", l.filePath, err)
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s: %w", l.filePath, ErrLocked)
+		}
+		time.Sleep(lockRetry)
+	}
+	// closing the file releases the lock
+	return fn()
+}
+
+// WriteFileAtomic writes the file through a temporary file in the same
+// directory that is synced and renamed over it, so a crash leaves either the
+// old or the new content. The permissions of an existing file are kept.
+func WriteFileAtomic(filePath string, write func(w io.Writer) error) error {
+	dir := filepath.Dir(filePath)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if info, err := os.Stat(filePath); err == nil {
+		if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+
+	if err := write(tmp); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filePath); err != nil {
+		return err
+	}
+	committed = true
+
+	// the rename is durable once the directory is synced
+	return syncDir(dir)
+}
@@ -0,0 +1,16 @@
+//go:build !unix || aix
+
+package persistence
+
+import "os"
+
+// tryLock always succeeds, flock is not available on this platform so only
+// the goroutines of this process are serialized
+func tryLock(file *os.File) (bool, error) {
+	return true, nil
+}
+
+// syncDir does nothing, directories cannot be synced on this platform
+func syncDir(dir string) error {
+	return nil
+}
@@ -0,0 +1,58 @@
+package persistence
+
+import (
+	"strings"
+	"time"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+)
+
+// HistoryStore stores the prices observed by the scrapers
+type HistoryStore interface {
+	// AddObservation appends a price observation
+	AddObservation(obs item.PriceObservation) error
+	// LoadObservations returns the observations matching the filter ordered by time
+	LoadObservations(filter HistoryFilter) ([]item.PriceObservation, error)
+	// AssignItemID sets the item ID of the observations recorded for
+	// itemName before items had IDs
+	AssignItemID(itemName, itemID string) error
+}
+
+// HistoryFilter selects price observations, zero fields match everything.
+// With both ItemID and ItemName set, observations recorded before items had
+// IDs are matched by name.
+type HistoryFilter struct {
+	ItemID   string
+	ItemName string
+	Source   string
+	From     time.Time
+	To       time.Time
+}
+
+// Match reports whether the observation is selected by the filter
+func (f HistoryFilter) Match(obs item.PriceObservation) bool {
+	switch {
+	case f.ItemID != "" && f.ItemName != "":
+		if obs.ItemID != f.ItemID && (obs.ItemID != "" || obs.ItemName != f.ItemName) {
+			return false
+		}
+	case f.ItemID != "":
+		if obs.ItemID != f.ItemID {
+			return false
+		}
+	case f.ItemName != "":
+		if obs.ItemName != f.ItemName {
+			return false
+		}
+	}
+	if f.Source != "" && !strings.EqualFold(obs.Source, f.Source) {
+		return false
+	}
+	if !f.From.IsZero() && obs.ObservedAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && obs.ObservedAt.After(f.To) {
+		return false
+	}
+	return true
+}
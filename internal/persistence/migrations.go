@@ -0,0 +1,156 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// migration is a single versioned change to the SQLite schema
+type migration struct {
+	version     int
+	description string
+	up          func(tx *sql.Tx) error
+}
+
+// sqliteMigrations lists every schema change in the order it must be applied.
+// Never edit a migration that has been released, append a new one instead.
+var sqliteMigrations = []migration{
+	{
+		version:     1,
+		description: "create items table",
+		up: execStatements(
+			`CREATE TABLE items (
+				id               INTEGER PRIMARY KEY AUTOINCREMENT,
+				name             TEXT NOT NULL UNIQUE,
+				category         TEXT NOT NULL DEFAULT '',
+				producer         TEXT NOT NULL DEFAULT '',
+				max_price        REAL NOT NULL DEFAULT 0,
+				min_price        REAL NOT NULL DEFAULT 0,
+				scraping_sources TEXT NOT NULL DEFAULT '',
+				url              TEXT NOT NULL DEFAULT '',
+				created_at       TEXT NOT NULL,
+				updated_at       TEXT NOT NULL
+			)`,
+			`CREATE INDEX idx_items_category ON items (category)`,
+		),
+	},
+	{
+		version:     2,
+		description: "create price history table",
+		up: execStatements(
+			`CREATE TABLE price_history (
+				id          INTEGER PRIMARY KEY AUTOINCREMENT,
+				item_name   TEXT NOT NULL,
+				source      TEXT NOT NULL,
+				price       REAL NOT NULL,
+				url         TEXT NOT NULL DEFAULT '',
+				seller      TEXT NOT NULL DEFAULT '',
+				observed_at TEXT NOT NULL
+			)`,
+			`CREATE INDEX idx_price_history_item ON price_history (item_name, source, observed_at)`,
+			`CREATE INDEX idx_price_history_observed_at ON price_history (observed_at)`,
+		),
+	},
+	{
+		version:     3,
+		description: "add item keyword rules",
+		up: execStatements(
+			`ALTER TABLE items ADD COLUMN required_keywords TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE items ADD COLUMN excluded_keywords TEXT NOT NULL DEFAULT ''`,
+		),
+	},
+	{
+		version:     4,
+		description: "add stable item ids",
+		up: execStatements(
+			`ALTER TABLE items ADD COLUMN item_id TEXT NOT NULL DEFAULT ''`,
+			`UPDATE items SET item_id = lower(hex(randomblob(8))) WHERE item_id = ''`,
+			`CREATE UNIQUE INDEX idx_items_item_id ON items (item_id)`,
+			`ALTER TABLE price_history ADD COLUMN item_id TEXT NOT NULL DEFAULT ''`,
+			`UPDATE price_history SET item_id = COALESCE(
+				(SELECT item_id FROM items WHERE items.name = price_history.item_name), '')`,
+			`CREATE INDEX idx_price_history_item_id ON price_history (item_id, source, observed_at)`,
+		),
+	},
+	{
+		version:     5,
+		description: "add item source urls",
+		up: execStatements(
+			`ALTER TABLE items ADD COLUMN source_urls TEXT NOT NULL DEFAULT ''`,
+		),
+	},
+}
+
+// execStatements returns a migration step that runs each statement in order
+func execStatements(statements ...string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		for _, stmt := range statements {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// migrate applies every migration newer than the current schema version
+func migrate(db *sql.DB, migrations []migration) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version     INTEGER PRIMARY KEY,
+		description TEXT NOT NULL,
+		applied_at  TEXT NOT NULL
+	)`)
+	if err != nil {
+		return err
+	}
+
+	current, err := schemaVersion(db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+
+		if err := applyMigration(db, m); err != nil {
+			return fmt.Errorf("migration %d (%s): %v", m.version, m.description, err)
+		}
+	}
+
+	return nil
+}
+
+// schemaVersion returns the latest applied migration version
+func schemaVersion(db *sql.DB) (int, error) {
+	var version sql.NullInt64
+	err := db.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+	return int(version.Int64), nil
+}
+
+func applyMigration(db *sql.DB, m migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := m.up(tx); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO schema_migrations (version, description, applied_at) VALUES (?, ?, ?)`,
+		m.version, m.description, formatTime(time.Now()),
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,340 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	_ "modernc.org/sqlite" // pure Go SQLite driver
+)
+
+const itemColumns = `item_id, name, category, producer, max_price, min_price, scraping_sources, url, created_at, updated_at,
+	required_keywords, excluded_keywords, source_urls`
+
+// snapshotTables are the tables copied back when a snapshot is restored
+var snapshotTables = []string{"items", "price_history"}
+
+// SQLiteStore stores the wishlist in a SQLite database
+type SQLiteStore struct {
+	db       *sql.DB
+	filePath string
+	backups  *backupDir
+}
+
+// NewSQLiteStore opens the database at filePath and applies pending
+// migrations, backups are disabled until WithBackups is called
+func NewSQLiteStore(filePath string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", filePath+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrate(db, sqliteMigrations); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteStore{
+		db:       db,
+		filePath: filePath,
+		backups:  newBackupDir(filePath, BackupConfig{}),
+	}, nil
+}
+
+// WithBackups sets where snapshots of the database are kept and how many
+func (s *SQLiteStore) WithBackups(cfg BackupConfig) *SQLiteStore {
+	s.backups = newBackupDir(s.filePath, cfg)
+	return s
+}
+
+// Backup writes a compacted copy of the database to a new snapshot
+func (s *SQLiteStore) Backup(reason string) (*Backup, error) {
+	if !s.backups.enabled() {
+		return nil, nil
+	}
+
+	return s.backups.create(reason, func(path string) error {
+		_, err := s.db.Exec(`VACUUM INTO ?`, path)
+		return err
+	})
+}
+
+// ListBackups returns the snapshots of the database, newest first
+func (s *SQLiteStore) ListBackups() ([]Backup, error) {
+	return s.backups.list()
+}
+
+// RestoreBackup replaces the items and the price history with the ones of
+// the snapshot in a single transaction, so other processes using the
+// database never see a partial restore
+func (s *SQLiteStore) RestoreBackup(id string) (*Backup, error) {
+	backup, err := s.backups.find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// snapshots taken by older versions are migrated on a copy, which also
+	// keeps the snapshot when it is pruned by the backup below
+	snapshot, err := os.CreateTemp("", "wishlist-restore-*.db")
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Close()
+	defer func() {
+		for _, suffix := range []string{"", "-wal", "-shm"} {
+			os.Remove(snapshot.Name() + suffix)
+		}
+	}()
+
+	if err := copyFile(backup.Path, snapshot.Name()); err != nil {
+		return nil, err
+	}
+	migrated, err := NewSQLiteStore(snapshot.Name())
+	if err != nil {
+		return nil, fmt.Errorf("backup %s: %v", backup.ID, err)
+	}
+	migrated.Close()
+
+	replaced, err := s.Backup("restore")
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `ATTACH DATABASE ? AS snapshot`, snapshot.Name()); err != nil {
+		return nil, err
+	}
+	defer conn.ExecContext(ctx, `DETACH DATABASE snapshot`)
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for _, table := range snapshotTables {
+		if _, err := tx.Exec(`DELETE FROM main.` + table); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(`INSERT INTO main.` + table + ` SELECT * FROM snapshot.` + table); err != nil {
+			return nil, err
+		}
+	}
+
+	return replaced, tx.Commit()
+}
+
+// Close closes the database
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// LoadItems returns all items ordered by insertion
+func (s *SQLiteStore) LoadItems() ([]item.Item, error) {
+	rows, err := s.db.Query(`SELECT ` + itemColumns + ` FROM items ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []item.Item
+	for rows.Next() {
+		itm, err := scanItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, itm)
+	}
+
+	return items, rows.Err()
+}
+
+// AddItem inserts a new item
+func (s *SQLiteStore) AddItem(newItem item.Item) error {
+	_, err := s.db.Exec(
+		`INSERT INTO items (`+itemColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		itemValues(newItem)...,
+	)
+	return err
+}
+
+// UpdateItem updates the item with the same ID
+func (s *SQLiteStore) UpdateItem(updatedItem item.Item) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(
+		`UPDATE items SET name = ?, category = ?, producer = ?, max_price = ?, min_price = ?,
+			scraping_sources = ?, url = ?, created_at = ?, updated_at = ?,
+			required_keywords = ?, excluded_keywords = ?, source_urls = ?
+		WHERE item_id = ?`,
+		append(itemValues(updatedItem)[1:], updatedItem.ID)...,
+	)
+	if err != nil {
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return errors.New("item not found")
+	}
+
+	return tx.Commit()
+}
+
+// DeleteItem deletes the item with the given ID
+func (s *SQLiteStore) DeleteItem(id string) error {
+	_, err := s.db.Exec(`DELETE FROM items WHERE item_id = ?`, id)
+	return err
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanItem(row rowScanner) (item.Item, error) {
+	var (
+		itm                  item.Item
+		sources              string
+		createdAt, updatedAt string
+		required, excluded   string
+		sourceURLs           string
+	)
+
+	err := row.Scan(
+		&itm.ID,
+		&itm.Name,
+		&itm.Category,
+		&itm.Producer,
+		&itm.MaxPrice,
+		&itm.MinPrice,
+		&sources,
+		&itm.URL,
+		&createdAt,
+		&updatedAt,
+		&required,
+		&excluded,
+		&sourceURLs,
+	)
+	if err != nil {
+		return itm, err
+	}
+
+	itm.ScrapingSources = splitList(sources)
+	itm.RequiredKeywords = splitList(required)
+	itm.ExcludedKeywords = splitList(excluded)
+	itm.SourceURLs = ParseSourceURLs(sourceURLs)
+
+	if itm.CreatedAt, err = parseTime(createdAt); err != nil {
+		return itm, err
+	}
+	if itm.UpdatedAt, err = parseTime(updatedAt); err != nil {
+		return itm, err
+	}
+
+	return itm, nil
+}
+
+// itemValues returns the item fields in the order of itemColumns
+func itemValues(itm item.Item) []any {
+	return []any{
+		itm.ID,
+		itm.Name,
+		itm.Category,
+		itm.Producer,
+		itm.MaxPrice,
+		itm.MinPrice,
+		strings.Join(itm.ScrapingSources, ","),
+		itm.URL,
+		formatTime(itm.CreatedAt),
+		formatTime(itm.UpdatedAt),
+		strings.Join(itm.RequiredKeywords, ","),
+		strings.Join(itm.ExcludedKeywords, ","),
+		FormatSourceURLs(itm.SourceURLs),
+	}
+}
+
+// AddObservation inserts a price observation
+func (s *SQLiteStore) AddObservation(obs item.PriceObservation) error {
+	_, err := s.db.Exec(
+		`INSERT INTO price_history (item_id, item_name, source, price, url, seller, observed_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		obs.ItemID, obs.ItemName, obs.Source, obs.Price, obs.URL, obs.Seller, formatTime(obs.ObservedAt.UTC()),
+	)
+	return err
+}
+
+// AssignItemID sets the item ID of the observations of itemName without one
+func (s *SQLiteStore) AssignItemID(itemName, itemID string) error {
+	_, err := s.db.Exec(`UPDATE price_history SET item_id = ? WHERE item_id = '' AND item_name = ?`, itemID, itemName)
+	return err
+}
+
+// LoadObservations returns the observations matching the filter ordered by time
+func (s *SQLiteStore) LoadObservations(filter HistoryFilter) ([]item.PriceObservation, error) {
+	query := `SELECT item_id, item_name, source, price, url, seller, observed_at FROM price_history WHERE 1 = 1`
+	var args []any
+
+	switch {
+	case filter.ItemID != "" && filter.ItemName != "":
+		query += ` AND (item_id = ? OR (item_id = '' AND item_name = ?))`
+		args = append(args, filter.ItemID, filter.ItemName)
+	case filter.ItemID != "":
+		query += ` AND item_id = ?`
+		args = append(args, filter.ItemID)
+	case filter.ItemName != "":
+		query += ` AND item_name = ?`
+		args = append(args, filter.ItemName)
+	}
+	if filter.Source != "" {
+		query += ` AND source = ? COLLATE NOCASE`
+		args = append(args, filter.Source)
+	}
+	if !filter.From.IsZero() {
+		query += ` AND observed_at >= ?`
+		args = append(args, formatTime(filter.From.UTC()))
+	}
+	if !filter.To.IsZero() {
+		query += ` AND observed_at <= ?`
+		args = append(args, formatTime(filter.To.UTC()))
+	}
+	query += ` ORDER BY observed_at, id`
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var observations []item.PriceObservation
+	for rows.Next() {
+		var (
+			obs        item.PriceObservation
+			observedAt string
+		)
+		err := rows.Scan(&obs.ItemID, &obs.ItemName, &obs.Source, &obs.Price, &obs.URL, &obs.Seller, &observedAt)
+		if err != nil {
+			return nil, err
+		}
+		if obs.ObservedAt, err = parseTime(observedAt); err != nil {
+			return nil, err
+		}
+		observations = append(observations, obs)
+	}
+
+	return observations, rows.Err()
+}
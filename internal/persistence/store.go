@@ -0,0 +1,37 @@
+package persistence
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+)
+
+// Store is the storage backend used by the repository
+type Store interface {
+	// LoadItems returns all items in the store
+	LoadItems() ([]item.Item, error)
+	// AddItem appends a new item to the store
+	AddItem(newItem item.Item) error
+	// UpdateItem replaces the stored item that has the same ID
+	UpdateItem(updatedItem item.Item) error
+	// DeleteItem removes the item with the given ID
+	DeleteItem(id string) error
+}
+
+// Open returns the store matching the file extension: .db, .sqlite and
+// .sqlite3 files use SQLite, everything else uses CSV. Both keep snapshots
+// as set by backups.
+func Open(filePath string, backups BackupConfig) (Store, error) {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".db", ".sqlite", ".sqlite3":
+		store, err := NewSQLiteStore(filePath)
+		if err != nil {
+			return nil, err
+		}
+		return store.WithBackups(backups), nil
+	default:
+		history := NewCSVHistoryStore(filepath.Join(filepath.Dir(filePath), CSVHistoryFile))
+		return NewCSVStore(filePath).WithHistory(history).WithBackups(backups), nil
+	}
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/WellyngtonF/WishListCLI/internal/persistence"
+)
+
+// errNoBackups is returned when the store cannot take snapshots
+var errNoBackups = errors.New("the wishlist store does not support backups")
+
+// Backup takes a snapshot of the wishlist, it returns nil when backups are
+// disabled
+func (r *Repository) Backup(reason string) (*persistence.Backup, error) {
+	backuper, ok := r.store.(persistence.Backuper)
+	if !ok {
+		return nil, nil
+	}
+
+	backup, err := backuper.Backup(reason)
+	if err != nil {
+		r.logger.Error("error backing up the wishlist", "reason", reason, "err", err)
+		return nil, err
+	}
+	if backup != nil {
+		r.logger.Debug("backup created", "backup", backup.ID, "reason", reason)
+	}
+	return backup, nil
+}
+
+// ListBackups returns the snapshots of the wishlist, newest first
+func (r *Repository) ListBackups() ([]persistence.Backup, error) {
+	backuper, ok := r.store.(persistence.Backuper)
+	if !ok {
+		return nil, errNoBackups
+	}
+	return backuper.ListBackups()
+}
+
+// RestoreBackup replaces the wishlist with the snapshot with the given ID or
+// ID prefix. It returns the snapshot of the replaced wishlist, so the
+// restore can be undone, nil when backups are disabled.
+func (r *Repository) RestoreBackup(id string) (*persistence.Backup, error) {
+	backuper, ok := r.store.(persistence.Backuper)
+	if !ok {
+		return nil, errNoBackups
+	}
+
+	replaced, err := backuper.RestoreBackup(id)
+	if err != nil {
+		r.logger.Error("error restoring backup", "backup", id, "err", err)
+		return nil, err
+	}
+
+	if replaced != nil {
+		r.logger.Info("backup restored", "backup", id, "previous", replaced.ID)
+	} else {
+		r.logger.Info("backup restored", "backup", id)
+	}
+	return replaced, nil
+}
+
+// WithBackup takes a single snapshot and runs fn, the changes made by fn do
+// not take their own snapshots so they do not rotate out older ones
+func (r *Repository) WithBackup(reason string, fn func() error) error {
+	if _, err := r.Backup(reason); err != nil {
+		return err
+	}
+
+	r.batch++
+	defer func() { r.batch-- }()
+	return fn()
+}
+
+// backupBefore takes a snapshot before a destructive change, unless it is
+// part of a WithBackup call
+func (r *Repository) backupBefore(reason string) error {
+	if r.batch > 0 {
+		return nil
+	}
+	_, err := r.Backup(reason)
+	return err
+}
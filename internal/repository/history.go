@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	"github.com/WellyngtonF/WishListCLI/internal/persistence"
+)
+
+var errNoHistory = errors.New("price history is not configured")
+
+// RecordPrice stores a price observation for an item
+func (r *Repository) RecordPrice(obs item.PriceObservation) error {
+	if r.history == nil {
+		return errNoHistory
+	}
+
+	obs.Source = strings.TrimSpace(obs.Source)
+	if obs.ObservedAt.IsZero() {
+		obs.ObservedAt = time.Now()
+	}
+
+	if err := r.history.AddObservation(obs); err != nil {
+		r.logger.Error("error recording price", "item", obs.ItemName, "id", obs.ItemID, "source", obs.Source, "err", err)
+		return err
+	}
+
+	r.logger.Debug("price recorded", "item", obs.ItemName, "source", obs.Source, "price", obs.Price)
+	return nil
+}
+
+// PriceHistory returns the prices observed for an item, optionally limited
+// to one source and to the [from, to] range. Zero values match everything.
+// Prices recorded before the item had an ID are matched by its name.
+func (r *Repository) PriceHistory(itm item.Item, source string, from, to time.Time) ([]item.PriceObservation, error) {
+	if r.history == nil {
+		return nil, errNoHistory
+	}
+
+	return r.history.LoadObservations(persistence.HistoryFilter{
+		ItemID:   itm.ID,
+		ItemName: itm.Name,
+		Source:   strings.TrimSpace(source),
+		From:     from,
+		To:       to,
+	})
+}
+
+// LatestPrice returns the last price observed for an item on a source, or nil if none
+func (r *Repository) LatestPrice(itm item.Item, source string) (*item.PriceObservation, error) {
+	observations, err := r.PriceHistory(itm, source, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(observations) == 0 {
+		return nil, nil
+	}
+	return &observations[len(observations)-1], nil
+}
+
+// LatestPrices returns the last price observed on each source of the item,
+// keyed by the trimmed source name. Sources never scraped map to nil.
+func (r *Repository) LatestPrices(itm item.Item) (map[string]*item.PriceObservation, error) {
+	latest := make(map[string]*item.PriceObservation)
+	for _, source := range itm.ScrapingSources {
+		source = strings.TrimSpace(source)
+		if source == "" {
+			continue
+		}
+
+		obs, err := r.LatestPrice(itm, source)
+		if err != nil {
+			return nil, err
+		}
+		latest[source] = obs
+	}
+	return latest, nil
+}
+
+// BestLatestPrice returns the cheapest of the latest prices of the item
+// sources, or nil if none was scraped yet
+func (r *Repository) BestLatestPrice(itm item.Item) (*item.PriceObservation, error) {
+	latest, err := r.LatestPrices(itm)
+	if err != nil {
+		return nil, err
+	}
+
+	var best *item.PriceObservation
+	for _, obs := range latest {
+		if obs != nil && (best == nil || obs.Price < best.Price) {
+			best = obs
+		}
+	}
+	return best, nil
+}
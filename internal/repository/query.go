@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+)
+
+// SortField is an item field query results can be sorted by
+type SortField string
+
+// Fields accepted by ParseSortField
+const (
+	SortName       SortField = "name"
+	SortCategory   SortField = "category"
+	SortProducer   SortField = "producer"
+	SortMaxPrice   SortField = "max_price"
+	SortMinPrice   SortField = "min_price"
+	SortLastPrice  SortField = "last_price"
+	SortBestSource SortField = "best_source"
+	SortCreated    SortField = "created_at"
+	SortUpdated    SortField = "updated_at"
+)
+
+// SortFields lists every sort field
+var SortFields = []SortField{
+	SortName,
+	SortCategory,
+	SortProducer,
+	SortMaxPrice,
+	SortMinPrice,
+	SortLastPrice,
+	SortBestSource,
+	SortCreated,
+	SortUpdated,
+}
+
+// ParseSortField returns the sort field with the given name, ignoring case
+// and accepting dashes for underscores
+func ParseSortField(name string) (SortField, error) {
+	name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "-", "_")
+	for _, field := range SortFields {
+		if string(field) == name {
+			return field, nil
+		}
+	}
+	return "", fmt.Errorf("unknown sort field %q", name)
+}
+
+// Query selects, sorts and pages the wishlist items. Zero fields match
+// everything.
+type Query struct {
+	// Category and Producer match the item fields ignoring case
+	Category string
+	Producer string
+	// Source matches items scraped from the source, ignoring case
+	Source string
+	// PriceFrom and PriceTo select items whose MaxPrice is in the range
+	PriceFrom float64
+	PriceTo   float64
+	// HasDeal selects items whose best latest price is at or below MaxPrice
+	HasDeal bool
+	// UpdatedSince selects items updated at or after the time
+	UpdatedSince time.Time
+	// Search matches names containing its characters in order, ignoring
+	// case. Without SortBy the closest matches come first.
+	Search string
+	// SortBy sorts the results, the store order is kept when empty
+	SortBy SortField
+	Desc   bool
+	// Offset skips the first results and Limit caps their number when positive
+	Offset int
+	Limit  int
+}
+
+// QueryResult is an item selected by a query with its best latest price
+type QueryResult struct {
+	Item item.Item
+	// Best is the cheapest latest price of the item sources, nil when the
+	// item was never scraped
+	Best  *item.PriceObservation
+	score int
+}
+
+// Query returns the items selected by the query
+func (r *Repository) Query(q Query) ([]item.Item, error) {
+	results, err := r.query(q, q.HasDeal || q.sortsByPrice())
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make([]item.Item, len(results))
+	for i, result := range results {
+		selected[i] = result.Item
+	}
+	return selected, nil
+}
+
+// QueryPrices returns the items selected by the query with their best latest price
+func (r *Repository) QueryPrices(q Query) ([]QueryResult, error) {
+	return r.query(q, true)
+}
+
+// query selects, sorts and pages the items, reading their best latest price
+// when withPrices is set
+func (r *Repository) query(q Query, withPrices bool) ([]QueryResult, error) {
+	items, err := r.store.LoadItems()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []QueryResult
+	for _, itm := range items {
+		score, ok := q.match(itm)
+		if !ok {
+			continue
+		}
+
+		result := QueryResult{Item: itm, score: score}
+		if withPrices {
+			if result.Best, err = r.BestLatestPrice(itm); err != nil {
+				return nil, err
+			}
+		}
+		if q.HasDeal && !isDeal(itm, result.Best) {
+			continue
+		}
+
+		results = append(results, result)
+	}
+
+	q.sort(results)
+
+	if q.Offset > 0 {
+		results = results[min(q.Offset, len(results)):]
+	}
+	if q.Limit > 0 && len(results) > q.Limit {
+		results = results[:q.Limit]
+	}
+	return results, nil
+}
+
+// sortsByPrice reports whether sorting needs the best latest prices
+func (q Query) sortsByPrice() bool {
+	return q.SortBy == SortLastPrice || q.SortBy == SortBestSource
+}
+
+// match reports whether the item matches the fields not needing the price
+// history, and its search score
+func (q Query) match(itm item.Item) (int, bool) {
+	if q.Category != "" && !strings.EqualFold(strings.TrimSpace(itm.Category), strings.TrimSpace(q.Category)) {
+		return 0, false
+	}
+	if q.Producer != "" && !strings.EqualFold(strings.TrimSpace(itm.Producer), strings.TrimSpace(q.Producer)) {
+		return 0, false
+	}
+	if q.Source != "" && !hasSource(itm, q.Source) {
+		return 0, false
+	}
+	if q.PriceFrom > 0 && itm.MaxPrice < q.PriceFrom {
+		return 0, false
+	}
+	if q.PriceTo > 0 && itm.MaxPrice > q.PriceTo {
+		return 0, false
+	}
+	if !q.UpdatedSince.IsZero() && itm.UpdatedAt.Before(q.UpdatedSince) {
+		return 0, false
+	}
+	return fuzzyScore(itm.Name, q.Search)
+}
+
+// sort orders the results by the query sort field, or by search score
+func (q Query) sort(results []QueryResult) {
+	var less func(a, b QueryResult) int
+	switch q.SortBy {
+	case SortName:
+		less = compareText(func(r QueryResult) string { return r.Item.Name })
+	case SortCategory:
+		less = compareText(func(r QueryResult) string { return r.Item.Category })
+	case SortProducer:
+		less = compareText(func(r QueryResult) string { return r.Item.Producer })
+	case SortMaxPrice:
+		less = compareFloat(func(r QueryResult) float64 { return r.Item.MaxPrice })
+	case SortMinPrice:
+		less = compareFloat(func(r QueryResult) float64 { return r.Item.MinPrice })
+	case SortCreated:
+		less = func(a, b QueryResult) int { return a.Item.CreatedAt.Compare(b.Item.CreatedAt) }
+	case SortUpdated:
+		less = func(a, b QueryResult) int { return a.Item.UpdatedAt.Compare(b.Item.UpdatedAt) }
+	case SortLastPrice:
+		less = compareFloat(func(r QueryResult) float64 { return r.Best.Price })
+	case SortBestSource:
+		less = compareText(func(r QueryResult) string { return r.Best.Source })
+	default:
+		if q.Search == "" {
+			return
+		}
+		less = func(a, b QueryResult) int { return a.score - b.score }
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		// items never scraped come last in either direction
+		if q.sortsByPrice() && (results[i].Best == nil || results[j].Best == nil) {
+			return results[i].Best != nil && results[j].Best == nil
+		}
+		if q.Desc {
+			return less(results[j], results[i]) < 0
+		}
+		return less(results[i], results[j]) < 0
+	})
+}
+
+func compareText(field func(r QueryResult) string) func(a, b QueryResult) int {
+	return func(a, b QueryResult) int {
+		return strings.Compare(strings.ToLower(field(a)), strings.ToLower(field(b)))
+	}
+}
+
+func compareFloat(field func(r QueryResult) float64) func(a, b QueryResult) int {
+	return func(a, b QueryResult) int {
+		switch x, y := field(a), field(b); {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+}
+
+// hasSource reports whether the item is scraped from the source
+func hasSource(itm item.Item, source string) bool {
+	for _, s := range itm.ScrapingSources {
+		if strings.EqualFold(strings.TrimSpace(s), strings.TrimSpace(source)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDeal reports whether the best price is at or below the item MaxPrice
+func isDeal(itm item.Item, best *item.PriceObservation) bool {
+	return best != nil && itm.MaxPrice > 0 && best.Price <= itm.MaxPrice
+}
+
+// fuzzyScore reports whether text contains the characters of pattern in
+// order, ignoring case. Lower scores are closer matches: substrings score
+// their position, other matches score more the more spread out they are.
+func fuzzyScore(text, pattern string) (int, bool) {
+	text = strings.ToLower(text)
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "" {
+		return 0, true
+	}
+
+	if i := strings.Index(text, pattern); i >= 0 {
+		return utf8.RuneCountInString(text[:i]), true
+	}
+
+	start, end := -1, 0
+	rest := []rune(pattern)
+	for i, r := range []rune(text) {
+		if len(rest) == 0 {
+			break
+		}
+		if r == rest[0] {
+			if start < 0 {
+				start = i
+			}
+			end = i
+			rest = rest[1:]
+		}
+	}
+
+	if len(rest) > 0 {
+		return 0, false
+	}
+	return len([]rune(text)) + end - start, true
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"io"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	"github.com/WellyngtonF/WishListCLI/internal/persistence"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		text      string
+		pattern   string
+		wantScore int
+		wantOK    bool
+	}{
+		{"PS5", "ps5", 0, true},
+		{"Console PS5", "PS5", 8, true},
+		{"Câmera Canon", "canon", 7, true},
+		{"PlayStation 5", "ps5", 25, true},
+		{"PlayStation 5", "  ", 0, true},
+		{"PlayStation 5", "5sp", 0, false},
+		{"Xbox", "ps5", 0, false},
+		{"", "ps5", 0, false},
+	}
+
+	for _, tt := range tests {
+		score, ok := fuzzyScore(tt.text, tt.pattern)
+		if score != tt.wantScore || ok != tt.wantOK {
+			t.Errorf("fuzzyScore(%q, %q) = %d, %v, want %d, %v", tt.text, tt.pattern, score, ok, tt.wantScore, tt.wantOK)
+		}
+	}
+}
+
+func TestFuzzyScoreOrder(t *testing.T) {
+	// closer matches score lower: a prefix, a later substring, a tight
+	// spread match and a loose one
+	texts := []string{"PS5 Slim", "Console PS5", "Pad S5", "PlayStation 5"}
+
+	previous := -1
+	for _, text := range texts {
+		score, ok := fuzzyScore(text, "ps5")
+		if !ok {
+			t.Fatalf("fuzzyScore(%q) did not match", text)
+		}
+		if score <= previous {
+			t.Errorf("fuzzyScore(%q) = %d, want more than %d", text, score, previous)
+		}
+		previous = score
+	}
+}
+
+// newPricedRepository creates a wishlist where Console is a deal on Amazon,
+// Controller costs more than its maximum price on Mercado Livre and Desk was
+// never scraped
+func newPricedRepository(t *testing.T) *Repository {
+	t.Helper()
+
+	dir := t.TempDir()
+	repo := New(persistence.NewCSVStore(filepath.Join(dir, "wishlist.csv"))).
+		WithHistory(persistence.NewCSVHistoryStore(filepath.Join(dir, "price_history.csv"))).
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	items := []item.Item{
+		{ID: "1", Name: "Console", MaxPrice: 3000, ScrapingSources: []string{"Amazon"}},
+		{ID: "2", Name: "Controller", MaxPrice: 300, ScrapingSources: []string{"Mercado Livre"}},
+		{ID: "3", Name: "Desk", MaxPrice: 900, ScrapingSources: []string{"Amazon"}},
+	}
+	for _, itm := range items {
+		if err := repo.CreateItem(itm); err != nil {
+			t.Fatalf("CreateItem(%s) error = %v", itm.Name, err)
+		}
+	}
+
+	observed := time.Now()
+	prices := []item.PriceObservation{
+		{ItemID: "1", ItemName: "Console", Source: "Amazon", Price: 2800, ObservedAt: observed},
+		{ItemID: "2", ItemName: "Controller", Source: "Mercado Livre", Price: 350, ObservedAt: observed},
+	}
+	for _, obs := range prices {
+		if err := repo.RecordPrice(obs); err != nil {
+			t.Fatalf("RecordPrice(%s) error = %v", obs.ItemName, err)
+		}
+	}
+
+	return repo
+}
+
+func TestQueryPriceOrder(t *testing.T) {
+	repo := newPricedRepository(t)
+
+	tests := []struct {
+		query Query
+		want  []string
+	}{
+		{Query{}, []string{"Console", "Controller", "Desk"}},
+		{Query{SortBy: SortLastPrice}, []string{"Controller", "Console", "Desk"}},
+		{Query{SortBy: SortLastPrice, Desc: true}, []string{"Console", "Controller", "Desk"}},
+		{Query{SortBy: SortBestSource}, []string{"Console", "Controller", "Desk"}},
+		{Query{SortBy: SortBestSource, Desc: true}, []string{"Controller", "Console", "Desk"}},
+		{Query{HasDeal: true}, []string{"Console"}},
+		{Query{SortBy: SortLastPrice, Offset: 1, Limit: 1}, []string{"Console"}},
+	}
+
+	for _, tt := range tests {
+		items, err := repo.Query(tt.query)
+		if err != nil {
+			t.Fatalf("Query(%+v) error = %v", tt.query, err)
+		}
+		var got []string
+		for _, itm := range items {
+			got = append(got, itm.Name)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Query(%+v) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestQueryPrices(t *testing.T) {
+	repo := newPricedRepository(t)
+
+	results, err := repo.QueryPrices(Query{SortBy: SortName})
+	if err != nil {
+		t.Fatalf("QueryPrices() error = %v", err)
+	}
+
+	want := []struct {
+		name   string
+		source string
+		price  float64
+	}{
+		{"Console", "Amazon", 2800},
+		{"Controller", "Mercado Livre", 350},
+		{"Desk", "", 0},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("QueryPrices() returned %d items, want %d", len(results), len(want))
+	}
+	for i, w := range want {
+		result := results[i]
+		if result.Item.Name != w.name {
+			t.Errorf("QueryPrices()[%d] = %s, want %s", i, result.Item.Name, w.name)
+			continue
+		}
+		if w.source == "" {
+			if result.Best != nil {
+				t.Errorf("best price of %s = %+v, want nil", w.name, *result.Best)
+			}
+			continue
+		}
+		if result.Best == nil || result.Best.Source != w.source || result.Best.Price != w.price {
+			t.Errorf("best price of %s = %+v, want %.2f on %s", w.name, result.Best, w.price, w.source)
+		}
+	}
+}
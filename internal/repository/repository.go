@@ -2,17 +2,48 @@ package repository
 
 import (
 	"errors"
+	"log/slog"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/WellyngtonF/WishListCLI/internal/item"
 	"github.com/WellyngtonF/WishListCLI/internal/persistence"
 )
 
-const filePath = "wishlist.csv" // Define the file path for CSV
+// Repository manages the wishlist on top of a storage backend
+type Repository struct {
+	store   persistence.Store
+	history persistence.HistoryStore
+	logger  *slog.Logger
+	// batch is set inside WithBackup, changes do not take their own snapshot
+	batch int
+}
+
+// New creates a repository backed by the given store
+func New(store persistence.Store) *Repository {
+	return &Repository{store: store, logger: slog.Default()}
+}
+
+// WithLogger sets the logger recording the changes to the wishlist
+func (r *Repository) WithLogger(logger *slog.Logger) *Repository {
+	r.logger = logger
+	return r
+}
+
+// WithHistory sets the store used to record price history
+func (r *Repository) WithHistory(history persistence.HistoryStore) *Repository {
+	r.history = history
+	return r
+}
+
+// errNotFound is returned when no item has the given ID or name
+var errNotFound = errors.New("item not found")
 
-// CreateItem adds a new item to the wishlist
-func CreateItem(newItem item.Item) error {
-	items, err := persistence.LoadItems(filePath)
+// CreateItem adds a new item to the wishlist. Items without an ID, or with
+// one already in use, get a new ID.
+func (r *Repository) CreateItem(newItem item.Item) error {
+	items, err := r.store.LoadItems()
 	if err != nil {
 		return err
 	}
@@ -22,49 +53,135 @@ func CreateItem(newItem item.Item) error {
 		if itm.Name == newItem.Name {
 			return errors.New("item already exists")
 		}
+		if itm.ID == newItem.ID {
+			newItem.ID = ""
+		}
+	}
+
+	if newItem.ID == "" {
+		newItem.ID = item.NewID()
 	}
 
 	// Set timestamps
 	newItem.CreatedAt = time.Now()
 	newItem.UpdatedAt = time.Now()
 
-	return persistence.AddItem(filePath, newItem)
+	return r.logChange("created", newItem, r.store.AddItem(newItem))
+}
+
+// GetItem fetches an item by ID
+func (r *Repository) GetItem(id string) (*item.Item, error) {
+	return r.findItem(func(itm item.Item) bool {
+		return itm.ID == id
+	})
 }
 
 // ReadItem fetches an item by name
-func ReadItem(name string) (*item.Item, error) {
-	items, err := persistence.LoadItems(filePath)
+func (r *Repository) ReadItem(name string) (*item.Item, error) {
+	return r.findItem(func(itm item.Item) bool {
+		return itm.Name == name
+	})
+}
+
+// FindItem fetches an item by ID, or by name when no item has that ID
+func (r *Repository) FindItem(idOrName string) (*item.Item, error) {
+	if itm, err := r.GetItem(idOrName); err != errNotFound {
+		return itm, err
+	}
+	return r.ReadItem(idOrName)
+}
+
+// findItem returns the first item matching match
+func (r *Repository) findItem(match func(itm item.Item) bool) (*item.Item, error) {
+	items, err := r.store.LoadItems()
 	if err != nil {
 		return nil, err
 	}
 
 	for _, itm := range items {
-		if itm.Name == name {
+		if match(itm) {
 			return &itm, nil
 		}
 	}
 
-	return nil, errors.New("item not found")
+	return nil, errNotFound
 }
 
-// UpdateItem modifies an existing item
-func UpdateItem(updatedItem item.Item) error {
+// UpdateItem modifies the item with the same ID, which may be renamed as
+// long as no other item has the new name. Items without an ID are looked
+// up by name.
+func (r *Repository) UpdateItem(updatedItem item.Item) error {
+	var current *item.Item
+	var err error
+	if updatedItem.ID != "" {
+		current, err = r.GetItem(updatedItem.ID)
+	} else {
+		current, err = r.ReadItem(updatedItem.Name)
+	}
+	if err != nil {
+		return err
+	}
+	updatedItem.ID = current.ID
+
+	if current.Name != updatedItem.Name {
+		if _, err := r.ReadItem(updatedItem.Name); err == nil {
+			return errors.New("item already exists")
+		}
+	}
+
+	if err := r.backupBefore("update"); err != nil {
+		return err
+	}
+
+	if current.Name != updatedItem.Name {
+		// the prices recorded by name only must follow the item
+		if r.history != nil {
+			if err := r.history.AssignItemID(current.Name, current.ID); err != nil {
+				return r.logChange("renamed", *current, err)
+			}
+		}
+	}
+
 	updatedItem.UpdatedAt = time.Now()
-	return persistence.UpdateItem(filePath, updatedItem)
+	if err := r.store.UpdateItem(updatedItem); err != nil || current.Name == updatedItem.Name {
+		return r.logChange("updated", updatedItem, err)
+	}
+
+	r.logger.Info("item renamed", "item", current.Name, "name", updatedItem.Name, "id", updatedItem.ID)
+	return nil
 }
 
-// DeleteItem removes an item from the wishlist
-func DeleteItem(name string) error {
-	return persistence.DeleteItem(filePath, name)
+// DeleteItem removes the item with the given ID from the wishlist
+func (r *Repository) DeleteItem(id string) error {
+	itm, err := r.GetItem(id)
+	if err != nil {
+		return err
+	}
+	if err := r.backupBefore("delete"); err != nil {
+		return err
+	}
+	return r.logChange("deleted", *itm, r.store.DeleteItem(id))
+}
+
+// RestoreItem adds back a deleted item keeping its ID and timestamps
+func (r *Repository) RestoreItem(deleted item.Item) error {
+	if _, err := r.GetItem(deleted.ID); err == nil {
+		return errors.New("item already exists")
+	}
+	if _, err := r.ReadItem(deleted.Name); err == nil {
+		return errors.New("item already exists")
+	}
+
+	return r.logChange("restored", deleted, r.store.AddItem(deleted))
 }
 
 // ListItems returns all items in the wishlist
-func ListItems() ([]item.Item, error) {
-	return persistence.LoadItems(filePath)
+func (r *Repository) ListItems() ([]item.Item, error) {
+	return r.store.LoadItems()
 }
 
-func GetItemsNames() ([]string, error) {
-	items, err := persistence.LoadItems(filePath)
+func (r *Repository) GetItemsNames() ([]string, error) {
+	items, err := r.store.LoadItems()
 	if err != nil {
 		return nil, err
 	}
@@ -75,3 +192,37 @@ func GetItemsNames() ([]string, error) {
 	}
 	return names, nil
 }
+
+// Categories returns the categories in use, sorted and without duplicates
+func (r *Repository) Categories() ([]string, error) {
+	items, err := r.store.LoadItems()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var categories []string
+	for _, itm := range items {
+		category := strings.TrimSpace(itm.Category)
+		if category != "" && !seen[category] {
+			seen[category] = true
+			categories = append(categories, category)
+		}
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		return strings.ToLower(categories[i]) < strings.ToLower(categories[j])
+	})
+	return categories, nil
+}
+
+// logChange logs a change to an item and returns err
+func (r *Repository) logChange(action string, itm item.Item, err error) error {
+	if err != nil {
+		r.logger.Error("error saving item", "action", action, "item", itm.Name, "id", itm.ID, "err", err)
+		return err
+	}
+
+	r.logger.Info("item "+action, "item", itm.Name, "id", itm.ID)
+	return nil
+}
@@ -0,0 +1,162 @@
+package matcher
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	"github.com/spf13/viper"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultThreshold is the minimum score of a relevant listing
+const DefaultThreshold = 0.5
+
+// defaultAccessoryWords are words of listings selling accessories instead of the product
+var defaultAccessoryWords = []string{
+	"capa", "case", "capinha", "skin", "adesivo", "pelicula", "suporte",
+	"controle", "cabo", "carregador", "bolsa", "mochila",
+	"protetor", "cover", "sticker", "stand", "charger", "cable",
+}
+
+// Weights of each item field in the score
+const (
+	nameWeight     = 0.7
+	producerWeight = 0.2
+	categoryWeight = 0.1
+)
+
+// Match is the relevance of a listing title for an item
+type Match struct {
+	// Score goes from 0 (unrelated) to 1 (every item field found)
+	Score float64
+	// Reason explains a zero score caused by the keyword rules
+	Reason string
+}
+
+// Matcher scores listing titles against the item name, producer and category
+type Matcher struct {
+	Threshold      float64
+	AccessoryWords []string
+}
+
+// New creates a matcher configured from the matching section of the config:
+//
+//	matching:
+//	  threshold: 0.5
+//	  accessory_words: [capa, case, skin]
+func New() *Matcher {
+	m := &Matcher{
+		Threshold:      DefaultThreshold,
+		AccessoryWords: defaultAccessoryWords,
+	}
+
+	if viper.IsSet("matching.threshold") {
+		m.Threshold = viper.GetFloat64("matching.threshold")
+	}
+	if words := viper.GetStringSlice("matching.accessory_words"); len(words) > 0 {
+		m.AccessoryWords = words
+	}
+
+	return m
+}
+
+// Accept reports whether the match is relevant enough
+func (m *Matcher) Accept(match Match) bool {
+	return match.Score >= m.Threshold
+}
+
+// Score returns the relevance of the listing title for the item. Titles with
+// an excluded keyword, without a required keyword or with an accessory word
+// that is not part of the item name score zero.
+func (m *Matcher) Score(itm item.Item, title string) Match {
+	titleTokens := tokenSet(title)
+	nameTokens := tokens(itm.Name)
+
+	for _, keyword := range itm.ExcludedKeywords {
+		if containsAll(titleTokens, tokens(keyword)) {
+			return Match{Reason: fmt.Sprintf("excluded keyword %q", keyword)}
+		}
+	}
+
+	for _, keyword := range itm.RequiredKeywords {
+		if !containsAll(titleTokens, tokens(keyword)) {
+			return Match{Reason: fmt.Sprintf("missing required keyword %q", keyword)}
+		}
+	}
+
+	nameSet := tokenSet(itm.Name)
+	for _, word := range m.AccessoryWords {
+		word = normalize(word)
+		if titleTokens[word] && !nameSet[word] {
+			return Match{Reason: fmt.Sprintf("accessory %q", word)}
+		}
+	}
+
+	score, weights := 0.0, 0.0
+	add := func(weight float64, fieldTokens []string) {
+		if len(fieldTokens) == 0 {
+			return
+		}
+		weights += weight
+		score += weight * overlap(titleTokens, fieldTokens)
+	}
+
+	add(nameWeight, nameTokens)
+	add(producerWeight, tokens(itm.Producer))
+	add(categoryWeight, tokens(itm.Category))
+
+	if weights == 0 {
+		return Match{Score: 1}
+	}
+
+	return Match{Score: score / weights}
+}
+
+// overlap returns the fraction of tokens present in set
+func overlap(set map[string]bool, tokens []string) float64 {
+	found := 0
+	for _, t := range tokens {
+		if set[t] {
+			found++
+		}
+	}
+	return float64(found) / float64(len(tokens))
+}
+
+func containsAll(set map[string]bool, tokens []string) bool {
+	for _, t := range tokens {
+		if !set[t] {
+			return false
+		}
+	}
+	return len(tokens) > 0
+}
+
+// tokens splits the text in lowercase words without accents
+func tokens(text string) []string {
+	return strings.FieldsFunc(normalize(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func tokenSet(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, t := range tokens(text) {
+		set[t] = true
+	}
+	return set
+}
+
+// normalize lowercases the text and removes accents, "Película" becomes "pelicula"
+func normalize(text string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(t, strings.ToLower(text))
+	if err != nil {
+		return strings.ToLower(text)
+	}
+	return result
+}
@@ -0,0 +1,85 @@
+package matcher
+
+import (
+	"math"
+	"testing"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+)
+
+func TestScore(t *testing.T) {
+	ps5 := item.Item{Name: "PlayStation 5", Producer: "Sony", Category: "Games"}
+
+	tests := []struct {
+		name       string
+		itm        item.Item
+		title      string
+		wantScore  float64
+		wantReason string
+	}{
+		{"every field", ps5, "Console Sony PlayStation 5 Games", 1, ""},
+		{"name and producer", ps5, "Console Sony PlayStation 5 Slim", 0.9, ""},
+		{"name only", ps5, "PlayStation 5", 0.7, ""},
+		{"half the name", ps5, "Sony PlayStation 4", 0.55, ""},
+		{"unrelated", ps5, "Xbox Series X", 0, ""},
+		{"accents and case", item.Item{Name: "Câmera Canon"}, "CAMERA canon EOS", 1, ""},
+		{"empty item", item.Item{}, "anything", 1, ""},
+		{"accessory", ps5, "Capa para PlayStation 5", 0, `accessory "capa"`},
+		{"accessory with accent", item.Item{Name: "iPhone 15"}, "Película iPhone 15", 0, `accessory "pelicula"`},
+		{"accessory in the item name", item.Item{Name: "Capa Kindle"}, "Capa Kindle Paperwhite", 1, ""},
+		{
+			name:       "excluded keyword",
+			itm:        item.Item{Name: "PS5", ExcludedKeywords: []string{"digital edition"}},
+			title:      "PS5 Digital Edition",
+			wantReason: `excluded keyword "digital edition"`,
+		},
+		{
+			name:      "excluded keyword partly present",
+			itm:       item.Item{Name: "PS5", ExcludedKeywords: []string{"digital edition"}},
+			title:     "PS5 Edition",
+			wantScore: 1,
+		},
+		{
+			name:       "missing required keyword",
+			itm:        item.Item{Name: "PS5", RequiredKeywords: []string{"825gb"}},
+			title:      "PS5 1TB",
+			wantReason: `missing required keyword "825gb"`,
+		},
+		{
+			name:      "required keyword",
+			itm:       item.Item{Name: "PS5", RequiredKeywords: []string{"825GB"}},
+			title:     "PS5 825gb",
+			wantScore: 1,
+		},
+	}
+
+	m := &Matcher{Threshold: DefaultThreshold, AccessoryWords: defaultAccessoryWords}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := m.Score(tt.itm, tt.title)
+			if math.Abs(got.Score-tt.wantScore) > 1e-9 || got.Reason != tt.wantReason {
+				t.Errorf("Score(%q) = %v, %q, want %v, %q", tt.title, got.Score, got.Reason, tt.wantScore, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestAccept(t *testing.T) {
+	m := &Matcher{Threshold: 0.5}
+
+	tests := []struct {
+		score float64
+		want  bool
+	}{
+		{0, false},
+		{0.49, false},
+		{0.5, true},
+		{1, true},
+	}
+
+	for _, tt := range tests {
+		if got := m.Accept(Match{Score: tt.score}); got != tt.want {
+			t.Errorf("Accept(%v) = %v, want %v", tt.score, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,464 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/WellyngtonF/WishListCLI/internal/alert"
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	"github.com/WellyngtonF/WishListCLI/internal/repository"
+	"github.com/WellyngtonF/WishListCLI/internal/scraper/sources"
+	"github.com/spf13/viper"
+	"golang.org/x/net/publicsuffix"
+)
+
+const (
+	defaultWorkers     = 4
+	defaultConcurrency = 1
+	defaultDelay       = 2 * time.Second
+)
+
+// Job is one item scraped from one source
+type Job struct {
+	Item   item.Item
+	Source string
+	// URL is the product page scraped instead of searching the source, if any
+	URL string
+}
+
+// Result is the outcome of a Job
+type Result struct {
+	Item   item.Item
+	Source string
+	// URL is the product page scraped, empty when the source was searched
+	URL string
+	// Scrape holds the offers found, it may be set even when Err is not nil
+	Scrape *sources.ScrapeResult
+	// Alerts holds the alert events sent for the best offer
+	Alerts     []alert.Event
+	Err        error
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// Best returns the offer chosen for the item or nil when none was found
+func (r Result) Best() *sources.Offer {
+	return r.Scrape.Best()
+}
+
+// Status is the state of a job
+type Status string
+
+const (
+	StatusQueued  Status = "queued"
+	StatusRunning Status = "running"
+	StatusOK      Status = "ok"
+	StatusFailed  Status = "failed"
+)
+
+// Progress reports a job changing state
+type Progress struct {
+	// Index is the position of the job in the slice given to RunJobs
+	Index  int
+	Job    Job
+	Status Status
+	// Result is set once the job finished with StatusOK or StatusFailed
+	Result *Result
+}
+
+// DomainLimit limits the requests sent to a single site
+type DomainLimit struct {
+	// Concurrency is the number of requests running at the same time
+	Concurrency int `mapstructure:"concurrency"`
+	// Delay is the minimum time between two requests
+	Delay time.Duration `mapstructure:"delay"`
+}
+
+// Runner scrapes the whole wishlist with a bounded pool of workers
+type Runner struct {
+	repo         *repository.Repository
+	workers      int
+	defaultLimit DomainLimit
+	domainLimits map[string]DomainLimit
+	alerts       *alert.Evaluator
+	progress     func(Progress)
+	logger       *slog.Logger
+
+	mu       sync.Mutex
+	limiters map[string]*domainLimiter
+}
+
+// NewRunner creates a runner configured from the scraper section of the config:
+//
+//	scraper:
+//	  workers: 4
+//	  concurrency: 1
+//	  delay: 2s
+//	  domain_limits:
+//	    www.zoom.com.br:
+//	      concurrency: 1
+//	      delay: 5s
+func NewRunner(repo *repository.Repository) *Runner {
+	r := &Runner{
+		repo:    repo,
+		workers: defaultWorkers,
+		defaultLimit: DomainLimit{
+			Concurrency: defaultConcurrency,
+			Delay:       defaultDelay,
+		},
+		domainLimits: make(map[string]DomainLimit),
+		limiters:     make(map[string]*domainLimiter),
+		logger:       slog.Default(),
+	}
+
+	if workers := viper.GetInt("scraper.workers"); workers > 0 {
+		r.workers = workers
+	}
+	if concurrency := viper.GetInt("scraper.concurrency"); concurrency > 0 {
+		r.defaultLimit.Concurrency = concurrency
+	}
+	if viper.IsSet("scraper.delay") {
+		r.defaultLimit.Delay = viper.GetDuration("scraper.delay")
+	}
+
+	var limits map[string]DomainLimit
+	if err := viper.UnmarshalKey("scraper.domain_limits", &limits); err != nil {
+		r.logger.Error("error reading scraper.domain_limits", "err", err)
+	}
+	for domain, limit := range limits {
+		r.SetDomainLimit(domain, limit)
+	}
+
+	return r
+}
+
+// SetWorkers sets the number of jobs running at the same time
+func (r *Runner) SetWorkers(workers int) *Runner {
+	if workers > 0 {
+		r.workers = workers
+	}
+	return r
+}
+
+// SetDefaultLimit sets the limit of domains without their own limit
+func (r *Runner) SetDefaultLimit(limit DomainLimit) *Runner {
+	r.defaultLimit = limit
+	return r
+}
+
+// SetDomainLimit sets the limit of one site, shared by all its subdomains
+// like www.amazon.com.br and amazon.com.br
+func (r *Runner) SetDomainLimit(domain string, limit DomainLimit) *Runner {
+	r.domainLimits[siteOf(domain)] = limit
+	return r
+}
+
+// SetAlerts sets the evaluator checking every best offer for deals
+func (r *Runner) SetAlerts(alerts *alert.Evaluator) *Runner {
+	r.alerts = alerts
+	return r
+}
+
+// SetLogger sets the logger of the runner
+func (r *Runner) SetLogger(logger *slog.Logger) *Runner {
+	r.logger = logger
+	return r
+}
+
+// OnProgress sets a function called whenever a job changes state. It is
+// called from the worker goroutines and must be safe for concurrent use.
+func (r *Runner) OnProgress(progress func(Progress)) *Runner {
+	r.progress = progress
+	return r
+}
+
+// report sends the job state to the progress function, if any
+func (r *Runner) report(index int, job Job, status Status, result *Result) {
+	if r.progress != nil {
+		r.progress(Progress{Index: index, Job: job, Status: status, Result: result})
+	}
+}
+
+// Run scrapes every item of the wishlist from each of its sources and
+// returns one result per (item, source) pair
+func (r *Runner) Run(ctx context.Context) ([]Result, error) {
+	items, err := r.repo.ListItems()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.RunJobs(ctx, Jobs(items)), nil
+}
+
+// Jobs returns one job per (item, source) pair, scraping the product page
+// of the source when the item has one
+func Jobs(items []item.Item) []Job {
+	var jobs []Job
+	for _, itm := range items {
+		for _, source := range itm.ScrapingSources {
+			source = strings.TrimSpace(source)
+			if source == "" {
+				continue
+			}
+
+			job := Job{Item: itm, Source: source}
+			if src, ok := sources.Lookup(source); ok {
+				job.URL = ProductURL(itm, src)
+			}
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+// ProductURL returns the page scraped for the item on src: the URL set for
+// the source or, failing that, the item URL when it is on the source domains.
+// It is empty when the source must be searched.
+func ProductURL(itm item.Item, src sources.Source) string {
+	if page := sources.CanonicalURLs(itm.SourceURLs)[src.Name()]; page != "" {
+		return page
+	}
+
+	if itm.URL != "" && src.HandlesURL(itm.URL) {
+		return strings.TrimSpace(itm.URL)
+	}
+	return ""
+}
+
+// RunJobs runs the jobs and returns their results in the same order. Successful
+// results are recorded in the price history and checked for alerts. Once ctx
+// is done the remaining jobs fail with the context error.
+func (r *Runner) RunJobs(ctx context.Context, jobs []Job) []Result {
+	results := make([]Result, len(jobs))
+	indexes := make(chan int)
+
+	for i, job := range jobs {
+		r.report(i, job, StatusQueued, nil)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < r.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				r.report(i, jobs[i], StatusRunning, nil)
+				results[i] = r.runJob(ctx, jobs[i])
+
+				status := StatusOK
+				if results[i].Err != nil || results[i].Best() == nil {
+					status = StatusFailed
+				}
+				r.logResult(results[i])
+				r.report(i, jobs[i], status, &results[i])
+			}
+		}()
+	}
+
+	for i := range jobs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+func (r *Runner) runJob(ctx context.Context, job Job) (result Result) {
+	result = Result{
+		Item:      job.Item,
+		Source:    job.Source,
+		URL:       job.URL,
+		StartedAt: time.Now(),
+	}
+	defer func() {
+		result.FinishedAt = time.Now()
+	}()
+
+	if err := ctx.Err(); err != nil {
+		result.Err = err
+		return result
+	}
+
+	src, ok := sources.Lookup(job.Source)
+	if !ok {
+		result.Err = fmt.Errorf("unsupported source: %s", job.Source)
+		return result
+	}
+
+	// product pages may be on another site than the source searches
+	host := src.Domain()
+	if u, err := url.Parse(job.URL); job.URL != "" && err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+
+	limiter := r.limiter(host)
+	if err := limiter.acquire(ctx); err != nil {
+		result.Err = err
+		return result
+	}
+	defer limiter.release()
+
+	if job.URL != "" {
+		result.Scrape, result.Err = src.ScrapeURL(ctx, job.Item, job.URL)
+	} else {
+		result.Scrape, result.Err = src.Search(ctx, job.Item)
+	}
+	if result.Err != nil {
+		return result
+	}
+
+	previous, err := r.repo.LatestPrice(job.Item, result.Scrape.Source)
+	if err != nil {
+		result.Err = fmt.Errorf("error reading price history: %v", err)
+		return result
+	}
+
+	if err := recordBest(r.repo, job.Item, result.Scrape); err != nil {
+		result.Err = fmt.Errorf("error recording price: %v", err)
+		return result
+	}
+
+	if best := result.Best(); r.alerts != nil && best != nil {
+		result.Alerts, result.Err = r.alerts.Evaluate(job.Item, result.Scrape.Source, *best, previous)
+	}
+
+	return result
+}
+
+// recordBest records the best offer of the result in the price history
+func recordBest(repo *repository.Repository, itm item.Item, result *sources.ScrapeResult) error {
+	best := result.Best()
+	if best == nil {
+		return nil
+	}
+
+	return repo.RecordPrice(item.PriceObservation{
+		ItemID:   itm.ID,
+		ItemName: itm.Name,
+		Source:   result.Source,
+		Price:    best.Price,
+		URL:      best.URL,
+		Seller:   best.Seller,
+	})
+}
+
+// logResult logs the outcome of a job
+func (r *Runner) logResult(result Result) {
+	log := r.logger.With("item", result.Item.Name, "source", result.Source,
+		"duration", result.FinishedAt.Sub(result.StartedAt).Round(time.Millisecond))
+	if result.URL != "" {
+		log = log.With("page", result.URL)
+	}
+
+	if result.Err != nil {
+		log.Warn("scrape failed", "err", result.Err)
+		return
+	}
+
+	if best := result.Best(); best != nil {
+		log.Info("price found", "price", best.Price, "url", best.URL, "offers", len(result.Scrape.Offers))
+	}
+	for _, event := range result.Alerts {
+		log.Info("alert sent", "kind", event.Kind, "price", event.Price)
+	}
+}
+
+// limiter returns the limiter of the site the host is on, creating it on first use
+func (r *Runner) limiter(host string) *domainLimiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	site := siteOf(host)
+	if l, ok := r.limiters[site]; ok {
+		return l
+	}
+
+	limit, ok := r.domainLimits[site]
+	if !ok {
+		limit = r.defaultLimit
+	}
+
+	l := newDomainLimiter(limit)
+	r.limiters[site] = l
+	return l
+}
+
+// siteOf returns the registrable domain of the host, like amazon.com.br for
+// www.amazon.com.br, or the host itself for IP addresses and hosts without one
+func siteOf(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+	if net.ParseIP(host) != nil {
+		return host
+	}
+	if site, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+		return site
+	}
+	return host
+}
+
+// domainLimiter bounds the concurrent requests to a domain and spaces them by a delay
+type domainLimiter struct {
+	slots chan struct{}
+	delay time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func newDomainLimiter(limit DomainLimit) *domainLimiter {
+	concurrency := limit.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	return &domainLimiter{
+		slots: make(chan struct{}, concurrency),
+		delay: limit.Delay,
+	}
+}
+
+// acquire waits for a free slot and for the delay since the previous request
+func (l *domainLimiter) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(l.delay)
+	l.mu.Unlock()
+
+	wait := time.Until(start)
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.release()
+		return ctx.Err()
+	}
+}
+
+// release frees the slot taken by acquire
+func (l *domainLimiter) release() {
+	<-l.slots
+}
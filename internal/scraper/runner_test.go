@@ -0,0 +1,257 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	"github.com/WellyngtonF/WishListCLI/internal/persistence"
+	"github.com/WellyngtonF/WishListCLI/internal/repository"
+	"github.com/WellyngtonF/WishListCLI/internal/scraper/sources"
+)
+
+// fakeSource finds every item at its MaxPrice after work, recording how many
+// scrapes run at the same time and when they start
+type fakeSource struct {
+	name   string
+	domain string
+
+	mu         sync.Mutex
+	work       func(itm item.Item) time.Duration
+	hook       func(itm item.Item)
+	running    int
+	maxRunning int
+	starts     []time.Time
+}
+
+var (
+	fakeA = &fakeSource{name: "Fake A", domain: "www.shop-a.test"}
+	fakeB = &fakeSource{name: "Fake B", domain: "www.shop-b.test"}
+)
+
+func init() {
+	sources.Register(fakeA)
+	sources.Register(fakeB)
+}
+
+// reset clears the recorded scrapes and sets how long each one takes
+func (f *fakeSource) reset(work func(itm item.Item) time.Duration, hook func(itm item.Item)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.work, f.hook = work, hook
+	f.running, f.maxRunning, f.starts = 0, 0, nil
+}
+
+func (f *fakeSource) Name() string           { return f.name }
+func (f *fakeSource) Aliases() []string      { return nil }
+func (f *fakeSource) Domain() string         { return f.domain }
+func (f *fakeSource) HandlesURL(string) bool { return true }
+
+func (f *fakeSource) Search(ctx context.Context, itm item.Item) (*sources.ScrapeResult, error) {
+	return f.scrape(ctx, itm, "")
+}
+
+func (f *fakeSource) ScrapeURL(ctx context.Context, itm item.Item, url string) (*sources.ScrapeResult, error) {
+	return f.scrape(ctx, itm, url)
+}
+
+func (f *fakeSource) scrape(ctx context.Context, itm item.Item, url string) (*sources.ScrapeResult, error) {
+	f.mu.Lock()
+	f.running++
+	f.maxRunning = max(f.maxRunning, f.running)
+	f.starts = append(f.starts, time.Now())
+	work, hook := f.work, f.hook
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		f.running--
+		f.mu.Unlock()
+	}()
+
+	if hook != nil {
+		hook(itm)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var wait time.Duration
+	if work != nil {
+		wait = work(itm)
+	}
+	select {
+	case <-time.After(wait):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	return &sources.ScrapeResult{
+		Source:    f.name,
+		Offers:    []sources.Offer{{Title: itm.Name, Price: itm.MaxPrice, URL: url}},
+		BestIndex: 0,
+	}, nil
+}
+
+func newTestRunner(t *testing.T) *Runner {
+	t.Helper()
+
+	dir := t.TempDir()
+	repo := repository.New(persistence.NewCSVStore(filepath.Join(dir, "wishlist.csv"))).
+		WithHistory(persistence.NewCSVHistoryStore(filepath.Join(dir, "price_history.csv"))).
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	return NewRunner(repo).
+		SetLogger(slog.New(slog.NewTextHandler(io.Discard, nil))).
+		SetDefaultLimit(DomainLimit{Concurrency: 10})
+}
+
+// testJobs returns n jobs on src for items priced 1 to n
+func testJobs(src *fakeSource, n int) []Job {
+	jobs := make([]Job, n)
+	for i := range jobs {
+		itm := item.Item{ID: fmt.Sprintf("id%d", i), Name: fmt.Sprintf("Item %d", i), MaxPrice: float64(i + 1)}
+		jobs[i] = Job{Item: itm, Source: src.name}
+	}
+	return jobs
+}
+
+func TestRunJobsKeepsJobOrder(t *testing.T) {
+	// later jobs finish first
+	work := func(itm item.Item) time.Duration {
+		return time.Duration(20-itm.MaxPrice) * time.Millisecond
+	}
+	fakeA.reset(work, nil)
+	fakeB.reset(work, nil)
+
+	jobs := append(testJobs(fakeA, 6), testJobs(fakeB, 6)...)
+	runner := newTestRunner(t).SetWorkers(5)
+	results := runner.RunJobs(context.Background(), jobs)
+
+	if len(results) != len(jobs) {
+		t.Fatalf("results = %d, want %d", len(results), len(jobs))
+	}
+	for i, result := range results {
+		if result.Err != nil {
+			t.Errorf("result %d error: %v", i, result.Err)
+			continue
+		}
+		if result.Item.ID != jobs[i].Item.ID || result.Source != jobs[i].Source {
+			t.Errorf("result %d is %s on %s, want %s on %s", i, result.Item.ID, result.Source, jobs[i].Item.ID, jobs[i].Source)
+		}
+		if best := result.Best(); best == nil || best.Price != jobs[i].Item.MaxPrice {
+			t.Errorf("result %d best = %+v, want price %v", i, best, jobs[i].Item.MaxPrice)
+		}
+	}
+
+	latest, err := runner.repo.LatestPrice(jobs[3].Item, fakeA.name)
+	if err != nil || latest == nil || latest.Price != jobs[3].Item.MaxPrice {
+		t.Errorf("recorded price = %+v, %v, want %v", latest, err, jobs[3].Item.MaxPrice)
+	}
+}
+
+func TestRunJobsDomainConcurrency(t *testing.T) {
+	fakeA.reset(func(item.Item) time.Duration { return 15 * time.Millisecond }, nil)
+
+	// product pages on the bare domain share the limit of the source domain
+	jobs := testJobs(fakeA, 10)
+	for i := range jobs {
+		if i%2 == 0 {
+			jobs[i].URL = fmt.Sprintf("https://shop-a.test/p/%d", i)
+		}
+	}
+
+	runner := newTestRunner(t).
+		SetWorkers(8).
+		SetDomainLimit("www.shop-a.test", DomainLimit{Concurrency: 2})
+	for i, result := range runner.RunJobs(context.Background(), jobs) {
+		if result.Err != nil {
+			t.Fatalf("result %d error: %v", i, result.Err)
+		}
+	}
+
+	if fakeA.maxRunning != 2 {
+		t.Errorf("max concurrent scrapes = %d, want 2", fakeA.maxRunning)
+	}
+}
+
+func TestRunJobsDomainDelay(t *testing.T) {
+	const delay = 30 * time.Millisecond
+	fakeA.reset(nil, nil)
+
+	runner := newTestRunner(t).
+		SetWorkers(4).
+		SetDomainLimit("shop-a.test", DomainLimit{Concurrency: 4, Delay: delay})
+	runner.RunJobs(context.Background(), testJobs(fakeA, 4))
+
+	if len(fakeA.starts) != 4 {
+		t.Fatalf("scrapes = %d, want 4", len(fakeA.starts))
+	}
+	for i := 1; i < len(fakeA.starts); i++ {
+		// scrapes are recorded a little after the limiter lets them start
+		if gap := fakeA.starts[i].Sub(fakeA.starts[i-1]); gap < delay-5*time.Millisecond {
+			t.Errorf("scrape %d started %v after the previous one, want at least %v", i, gap, delay)
+		}
+	}
+}
+
+func TestRunJobsCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the first scrape cancels the run, the next job waits for the delay
+	fakeA.reset(nil, func(item.Item) { cancel() })
+
+	runner := newTestRunner(t).
+		SetWorkers(2).
+		SetDomainLimit("shop-a.test", DomainLimit{Concurrency: 1, Delay: time.Hour})
+
+	done := make(chan []Result)
+	go func() {
+		done <- runner.RunJobs(ctx, testJobs(fakeA, 5))
+	}()
+
+	var results []Result
+	select {
+	case results = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunJobs did not return after the context was cancelled")
+	}
+
+	if len(fakeA.starts) != 1 {
+		t.Errorf("scrapes = %d, want 1", len(fakeA.starts))
+	}
+	for i, result := range results {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("result %d error = %v, want %v", i, result.Err, context.Canceled)
+		}
+	}
+}
+
+func TestSiteOf(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"www.amazon.com.br", "amazon.com.br"},
+		{"amazon.com.br", "amazon.com.br"},
+		{"produto.mercadolivre.com.br", "mercadolivre.com.br"},
+		{"WWW.Zoom.com.br.", "zoom.com.br"},
+		{"localhost", "localhost"},
+		{"127.0.0.1", "127.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		if got := siteOf(tt.host); got != tt.want {
+			t.Errorf("siteOf(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
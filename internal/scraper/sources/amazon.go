@@ -1,91 +1,84 @@
 package sources
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"strconv"
-	"strings"
 
 	"github.com/WellyngtonF/WishListCLI/internal/item"
-	"github.com/WellyngtonF/WishListCLI/internal/scraper/utils"
 	"github.com/gocolly/colly"
 )
 
-func ScrapeAmazon(item item.Item) (float64, string, error) {
-	// https://www.zoom.com.br/search?q=ps5&hitsPerPage=24&refinements%5B0%5D%5Bid%5D=bestSellingMerchantName&refinements%5B0%5D%5Bvalues%5D%5B0%5D=Amazon&sortBy=default&enableRefinementsSuggestions=true&isDealsPage=false
-	searchURL := fmt.Sprintf("https://www.zoom.com.br/search?q=%s&refinements%%5B0%%5D%%5Bid%%5D=bestSellingMerchantName&refinements%%5B0%%5D%%5Bvalues%%5D%%5B0%%5D=Amazon&sortBy=default&enableRefinementsSuggestions=true&isDealsPage=false", item.Name)
-
-	c := colly.NewCollector(
-		colly.IgnoreRobotsTxt(),
-		colly.AllowedDomains("www.zoom.com.br"),
-	)
-
-	proxyURL, username, password, err := utils.GetRandomProxy()
-	if err != nil {
-		fmt.Printf("Error getting proxy: %v\n", err)
-	} else {
-		err = c.SetProxy(fmt.Sprintf("http://%s:%s@%s", username, password, proxyURL))
-		if err != nil {
-			fmt.Printf("Error setting proxy: %v\n", err)
-		}
-	}
-
-	c.UserAgent = "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:130.0) Gecko/20100101 Firefox/130.0"
-
-	type Product struct {
-		Price float64
-		URL   string
-	}
-
-	var products []Product
-	var lowestPriceProduct Product
+// amazon searches Amazon offers through the zoom.com.br price comparator
+type amazon struct {
+	base
+}
 
-	c.OnRequest(func(r *colly.Request) {
-		fmt.Println("Visiting", r.URL)
+func init() {
+	Register(&amazon{
+		base: base{
+			name:            "Amazon",
+			aliases:         []string{"amazon.com.br"},
+			domains:         []string{"www.zoom.com.br", "www.amazon.com.br"},
+			ignoreRobotsTxt: true,
+		},
 	})
+}
 
-	c.OnError(func(r *colly.Response, err error) {
-		fmt.Println("Error:", err)
-	})
+// Search searches the item on zoom.com.br filtered by the Amazon merchant
+func (a *amazon) Search(ctx context.Context, itm item.Item) (*ScrapeResult, error) {
+	// https://www.zoom.com.br/search?q=ps5&hitsPerPage=24&refinements%5B0%5D%5Bid%5D=bestSellingMerchantName&refinements%5B0%5D%5Bvalues%5D%5B0%5D=Amazon&sortBy=default&enableRefinementsSuggestions=true&isDealsPage=false
+	searchURL := fmt.Sprintf("https://www.zoom.com.br/search?q=%s&refinements%%5B0%%5D%%5Bid%%5D=bestSellingMerchantName&refinements%%5B0%%5D%%5Bvalues%%5D%%5B0%%5D=Amazon&sortBy=default&enableRefinementsSuggestions=true&isDealsPage=false", itm.Name)
 
-	c.OnHTML("div[data-testid='product-card']", func(e *colly.HTMLElement) {
+	c := a.newCollector(ctx)
+	result := newScrapeResult(a.name)
 
-		if len(products) >= 10 {
+	c.OnHTML("div[data-testid='product-card']", func(e *colly.HTMLElement) {
+		if len(result.Offers) >= maxOffers {
 			return
 		}
 
-		price := e.ChildText("p[data-testid='product-card::price']")
-		price = strings.ReplaceAll(price, "R$", "")
-		price = strings.TrimSpace(price)
-		price = strings.ReplaceAll(price, ".", "")
-		price = strings.ReplaceAll(price, ",", ".")
-		priceFloat, err := strconv.ParseFloat(price, 64)
+		price, err := parsePrice(e.ChildText("p[data-testid='product-card::price']"))
 		if err != nil {
-			fmt.Println("Error parsing price:", err)
-		}
-
-		if priceFloat < item.MinPrice {
+			a.log().Debug("error parsing price", "err", err)
 			return
 		}
 
+		title := e.ChildText("h2[data-testid='product-card::name']")
 		url := e.ChildAttr("a.ProductCard_ProductCard_Inner__gapsh", "href")
-		url = "https://www.zoom.com.br" + url
-		product := Product{Price: priceFloat, URL: url}
-		products = append(products, product)
 
-		if lowestPriceProduct.Price == 0 || priceFloat < lowestPriceProduct.Price {
-			lowestPriceProduct = product
-		}
+		result.add(Offer{
+			Title:        title,
+			Price:        price,
+			Seller:       a.name,
+			Availability: AvailabilityInStock,
+			Condition:    parseCondition(title),
+			Rating:       parseRating(e.ChildText("[data-testid='product-card::rating']")),
+			URL:          "https://www.zoom.com.br" + url,
+		})
 	})
 
-	err = c.Visit(searchURL)
-	if err != nil {
-		return 0, "", fmt.Errorf("error visiting URL: %v", err)
+	if err := visit(ctx, c, searchURL); err != nil {
+		return nil, err
 	}
 
-	if len(products) == 0 {
-		return 0, "", errors.New("no products found")
-	}
+	return result, result.choose(itm, true)
+}
 
-	return lowestPriceProduct.Price, lowestPriceProduct.URL, nil
+// ScrapeURL scrapes an Amazon or zoom.com.br product page
+func (a *amazon) ScrapeURL(ctx context.Context, itm item.Item, url string) (*ScrapeResult, error) {
+	return a.scrapeProductPage(ctx, itm, url, productPage{
+		prices: []selector{
+			{selector: "#corePrice_feature_div span.a-price span.a-offscreen"},
+			{selector: "span.a-price span.a-offscreen"},
+		},
+		titles: []selector{
+			{selector: "#productTitle"},
+			{selector: "h1[data-testid='product-name']"},
+		},
+		sellers: []selector{
+			{selector: "#sellerProfileTriggerId"},
+			{selector: "#merchantInfoFeature_feature_div span.offer-display-feature-text-message"},
+		},
+		outOfStock: []string{"#outOfStock"},
+	})
 }
@@ -0,0 +1,195 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/WellyngtonF/WishListCLI/internal/scraper/utils"
+	"github.com/gocolly/colly"
+)
+
+const userAgent = "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:130.0) Gecko/20100101 Firefox/130.0"
+
+// logger receives the requests and errors of the sources, nil uses slog.Default
+var logger *slog.Logger
+
+// SetLogger sets the logger of the sources
+func SetLogger(l *slog.Logger) {
+	logger = l
+}
+
+// maxOffers is the number of search results compared for the lowest price
+const maxOffers = 10
+
+// base holds what every source shares: its names, allowed domains and
+// the collector setup with proxy and user agent
+type base struct {
+	name            string
+	aliases         []string
+	domains         []string
+	ignoreRobotsTxt bool
+}
+
+// Name returns the source name
+func (b *base) Name() string {
+	return b.name
+}
+
+// Aliases returns other names accepted for the source
+func (b *base) Aliases() []string {
+	return b.aliases
+}
+
+// Domain returns the domain searched by the source, used for rate limiting
+func (b *base) Domain() string {
+	return b.domains[0]
+}
+
+// HandlesURL reports whether rawURL is on one of the source sites, with or
+// without www or on any other subdomain
+func (b *base) HandlesURL(rawURL string) bool {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, site := range b.sites() {
+		if host == site || strings.HasSuffix(host, "."+site) {
+			return true
+		}
+	}
+	return false
+}
+
+// sites returns the source domains without their www prefix
+func (b *base) sites() []string {
+	sites := make([]string, len(b.domains))
+	for i, domain := range b.domains {
+		sites[i] = strings.TrimPrefix(domain, "www.")
+	}
+	return sites
+}
+
+// urlFilters only let the collector visit the URLs accepted by HandlesURL
+func (b *base) urlFilters() []*regexp.Regexp {
+	filters := make([]*regexp.Regexp, len(b.domains))
+	for i, site := range b.sites() {
+		filters[i] = regexp.MustCompile(`(?i)^https?://([^/?#@]+\.)?` + regexp.QuoteMeta(site) + `(:[0-9]+)?([/?#]|$)`)
+	}
+	return filters
+}
+
+// log returns the logger of the source
+func (b *base) log() *slog.Logger {
+	l := logger
+	if l == nil {
+		l = slog.Default()
+	}
+	return l.With("source", b.name)
+}
+
+// newCollector returns a collector restricted to the source sites using a
+// random proxy. Requests are aborted once ctx is done.
+func (b *base) newCollector(ctx context.Context) *colly.Collector {
+	options := []func(*colly.Collector){
+		colly.URLFilters(b.urlFilters()...),
+	}
+	if b.ignoreRobotsTxt {
+		options = append(options, colly.IgnoreRobotsTxt())
+	}
+
+	c := colly.NewCollector(options...)
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	log := b.log()
+
+	proxyURL, username, password, err := utils.GetRandomProxy()
+	switch {
+	case errors.Is(err, utils.ErrNoProxy):
+		log.Debug("scraping without proxy")
+	case err != nil:
+		log.Warn("error getting proxy", "err", err)
+	default:
+		proxy, err := url.Parse(fmt.Sprintf("http://%s:%s@%s", username, password, proxyURL))
+		if err != nil {
+			log.Warn("error setting proxy", "proxy", proxyURL, "err", err)
+		} else {
+			transport.Proxy = http.ProxyURL(proxy)
+		}
+	}
+
+	c.WithTransport(&contextTransport{ctx: ctx, next: transport})
+	c.UserAgent = userAgent
+
+	c.OnRequest(func(r *colly.Request) {
+		if ctx.Err() != nil {
+			r.Abort()
+			return
+		}
+		log.Debug("visiting", "url", r.URL.String())
+	})
+
+	c.OnError(func(r *colly.Response, err error) {
+		log.Warn("request failed", "url", r.Request.URL.String(), "status", r.StatusCode, "err", err)
+	})
+
+	return c
+}
+
+// visit visits the URL and reports a cancelled context instead of the request error
+func visit(ctx context.Context, c *colly.Collector, url string) error {
+	err := c.Visit(url)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		return fmt.Errorf("error visiting URL: %v", err)
+	}
+	return nil
+}
+
+// contextTransport binds every request to ctx so in-flight requests are cancelled with it
+type contextTransport struct {
+	ctx  context.Context
+	next http.RoundTripper
+}
+
+// RoundTrip sends the request with the transport context
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.next.RoundTrip(req.WithContext(t.ctx))
+}
+
+// parsePrice converts prices like "R$ 1.234,56" or "1234.56" to float
+func parsePrice(text string) (float64, error) {
+	price := strings.ReplaceAll(text, "R$", "")
+	price = strings.TrimSpace(price)
+
+	// Brazilian format uses dots for thousands and a comma for decimals
+	if strings.Contains(price, ",") {
+		price = strings.ReplaceAll(price, ".", "")
+		price = strings.ReplaceAll(price, ",", ".")
+	} else if dots := strings.Count(price, "."); dots > 1 || (dots == 1 && len(price)-strings.Index(price, ".") == 4) {
+		// only thousands separators, as in the fraction part "3.799"
+		price = strings.ReplaceAll(price, ".", "")
+	}
+
+	return strconv.ParseFloat(price, 64)
+}
+
+// parseRating converts ratings like "4,8" or "4.8" to float, returning 0 when missing
+func parseRating(text string) float64 {
+	rating, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(text), ",", "."), 64)
+	if err != nil {
+		return 0
+	}
+	return rating
+}
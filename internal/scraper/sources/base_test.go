@@ -0,0 +1,58 @@
+package sources
+
+import "testing"
+
+func TestHandlesURL(t *testing.T) {
+	amazon, _ := Lookup("Amazon")
+	mercadoLivre, _ := Lookup("Mercado Livre")
+
+	tests := []struct {
+		src  Source
+		url  string
+		want bool
+	}{
+		{amazon, "https://www.amazon.com.br/dp/B0CL5KNB9M", true},
+		{amazon, "https://amazon.com.br/dp/B0CL5KNB9M", true},
+		{amazon, "  HTTPS://WWW.AMAZON.COM.BR/dp/B0CL5KNB9M  ", true},
+		{amazon, "https://www.amazon.com/dp/B0CL5KNB9M", false},
+		{amazon, "https://notamazon.com.br/dp/B0CL5KNB9M", false},
+		{amazon, "https://www.mercadolivre.com.br/p/MLB123", false},
+		{mercadoLivre, "https://produto.mercadolivre.com.br/MLB-123-ps5", true},
+		{mercadoLivre, "https://mercadolivre.com.br/p/MLB123", true},
+		{mercadoLivre, "https://www.mercadolivre.com.br/p/MLB123", true},
+		{mercadoLivre, "https://mercadolivre.com.br.example.com/p/MLB123", false},
+		{mercadoLivre, "not a url", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.src.HandlesURL(tt.url); got != tt.want {
+			t.Errorf("%s HandlesURL(%q) = %v, want %v", tt.src.Name(), tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestURLFilters(t *testing.T) {
+	src, _ := Lookup("Amazon")
+	filters := src.(*amazon).urlFilters()
+
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://amazon.com.br/dp/B0", true},
+		{"https://www.amazon.com.br", true},
+		{"https://www.zoom.com.br/search?q=ps5", true},
+		{"https://amazon.com.br.example.com/dp/B0", false},
+		{"https://example.com/?next=https://amazon.com.br", false},
+	}
+
+	for _, tt := range tests {
+		got := false
+		for _, filter := range filters {
+			got = got || filter.MatchString(tt.url)
+		}
+		if got != tt.want {
+			t.Errorf("urlFilters match %q = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
@@ -1,53 +1,38 @@
 package sources
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/WellyngtonF/WishListCLI/internal/item"
-	"github.com/WellyngtonF/WishListCLI/internal/scraper/utils"
 	"github.com/gocolly/colly"
 )
 
-func ScrapeMercadoLivre(item item.Item) (float64, string, error) {
-	searchURL := fmt.Sprintf("https://lista.mercadolivre.com.br/%s", strings.ReplaceAll(item.Name, " ", "-"))
-
-	c := colly.NewCollector(
-		colly.AllowedDomains("www.mercadolivre.com.br", "lista.mercadolivre.com.br"),
-	)
-
-	proxyURL, username, password, err := utils.GetRandomProxy()
-	if err != nil {
-		fmt.Printf("Error getting proxy: %v\n", err)
-	} else {
-		err = c.SetProxy(fmt.Sprintf("http://%s:%s@%s", username, password, proxyURL))
-		if err != nil {
-			fmt.Printf("Error setting proxy: %v\n", err)
-		}
-	}
-
-	c.UserAgent = "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:130.0) Gecko/20100101 Firefox/130.0"
-
-	type Product struct {
-		Price float64
-		URL   string
-	}
-
-	var products []Product
-	var lowestPriceProduct Product
+// mercadoLivre searches lista.mercadolivre.com.br
+type mercadoLivre struct {
+	base
+}
 
-	c.OnRequest(func(r *colly.Request) {
-		fmt.Println("Visiting", r.URL)
+func init() {
+	Register(&mercadoLivre{
+		base: base{
+			name:    "Mercado Livre",
+			aliases: []string{"mercadolivre", "ml"},
+			domains: []string{"www.mercadolivre.com.br", "lista.mercadolivre.com.br", "produto.mercadolivre.com.br"},
+		},
 	})
+}
 
-	c.OnError(func(r *colly.Response, err error) {
-		fmt.Println("Error:", err)
-	})
+// Search searches the item on the Mercado Livre listing
+func (m *mercadoLivre) Search(ctx context.Context, itm item.Item) (*ScrapeResult, error) {
+	searchURL := fmt.Sprintf("https://lista.mercadolivre.com.br/%s", strings.ReplaceAll(itm.Name, " ", "-"))
+
+	c := m.newCollector(ctx)
+	result := newScrapeResult(m.name)
 
 	c.OnHTML("li.ui-search-layout__item", func(e *colly.HTMLElement) {
-		if len(products) >= 10 {
+		if len(result.Offers) >= maxOffers {
 			return
 		}
 
@@ -56,35 +41,53 @@ func ScrapeMercadoLivre(item item.Item) (float64, string, error) {
 			url = e.ChildAttr("a.ui-search-link__title-card", "href")
 		}
 
-		priceStr := e.ChildText("div.ui-search-price__second-line span.ui-search-price__part--medium span.andes-money-amount__fraction")
-		priceStr = strings.TrimSpace(priceStr)
-		priceStr = strings.ReplaceAll(priceStr, ".", "")
-		price, err := strconv.ParseFloat(priceStr, 64)
+		price, err := parsePrice(e.ChildText("div.ui-search-price__second-line span.ui-search-price__part--medium span.andes-money-amount__fraction"))
 		if err != nil {
-			fmt.Printf("Error parsing price: %v\n", err)
+			m.log().Debug("error parsing price", "err", err)
 			return
 		}
 
-		if price < item.MinPrice {
-			return
+		if cents, err := parsePrice(e.ChildText("div.ui-search-price__second-line span.ui-search-price__part--medium span.andes-money-amount__cents")); err == nil {
+			price += cents / 100
 		}
 
-		product := Product{Price: price, URL: url}
-		products = append(products, product)
-
-		if lowestPriceProduct.Price == 0 || price < lowestPriceProduct.Price {
-			lowestPriceProduct = product
-		}
+		title := e.ChildText("h2.ui-search-item__title, .poly-component__title")
+		shippingCost, freeShipping := parseShipping(e.ChildText("p.ui-search-item__shipping, .poly-component__shipping"))
+
+		result.add(Offer{
+			Title:        title,
+			Price:        price,
+			Seller:       e.ChildText("p.ui-search-official-store-label, .poly-component__seller"),
+			ShippingCost: shippingCost,
+			FreeShipping: freeShipping,
+			Availability: AvailabilityInStock,
+			Condition:    parseCondition(title + " " + e.ChildText("span.ui-search-item__group__element.ui-search-item__details, .poly-component__item-condition")),
+			Rating:       parseRating(e.ChildText("span.ui-search-reviews__rating-number, .poly-reviews__rating")),
+			URL:          url,
+		})
 	})
 
-	err = c.Visit(searchURL)
-	if err != nil {
-		return 0, "", fmt.Errorf("error visiting URL: %v", err)
+	if err := visit(ctx, c, searchURL); err != nil {
+		return nil, err
 	}
 
-	if len(products) == 0 {
-		return 0, "", errors.New("no products found")
-	}
+	return result, result.choose(itm, true)
+}
 
-	return lowestPriceProduct.Price, lowestPriceProduct.URL, nil
+// ScrapeURL scrapes a Mercado Livre product page
+func (m *mercadoLivre) ScrapeURL(ctx context.Context, itm item.Item, url string) (*ScrapeResult, error) {
+	return m.scrapeProductPage(ctx, itm, url, productPage{
+		prices: []selector{
+			{selector: "div.ui-pdp-price__second-line meta[itemprop='price']", attr: "content"},
+			{selector: "div.ui-pdp-price__second-line span.andes-money-amount__fraction"},
+		},
+		titles: []selector{
+			{selector: "h1.ui-pdp-title"},
+		},
+		sellers: []selector{
+			{selector: "div.ui-pdp-seller__header__title span"},
+			{selector: "button.ui-pdp-seller__link-trigger-button span"},
+		},
+		outOfStock: []string{"div.ui-pdp-stock-information__title--out-of-stock"},
+	})
 }
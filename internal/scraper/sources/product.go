@@ -0,0 +1,248 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	"github.com/gocolly/colly"
+)
+
+// selector locates a value on a page, in the attr attribute of the element
+// or in its text when attr is empty
+type selector struct {
+	selector string
+	attr     string
+}
+
+// productPage locates the parts of a source product page. Each list is tried
+// in order before the structured data most stores publish.
+type productPage struct {
+	prices  []selector
+	titles  []selector
+	sellers []selector
+	// outOfStock matches elements only shown when the product cannot be bought
+	outOfStock []string
+}
+
+// Structured data read when the source selectors do not match
+var (
+	metaPriceSelectors = []selector{
+		{selector: "meta[itemprop='price']", attr: "content"},
+		{selector: "meta[property='product:price:amount']", attr: "content"},
+	}
+	metaTitleSelectors = []selector{
+		{selector: "meta[property='og:title']", attr: "content"},
+	}
+	metaAvailabilitySelectors = []selector{
+		{selector: "link[itemprop='availability']", attr: "href"},
+		{selector: "meta[itemprop='availability']", attr: "content"},
+		{selector: "meta[property='product:availability']", attr: "content"},
+	}
+)
+
+// scrapeProductPage visits a product page and returns it as a single offer.
+// Pages without a price fail, as the price is all a scrape records.
+func (b *base) scrapeProductPage(ctx context.Context, itm item.Item, url string, page productPage) (*ScrapeResult, error) {
+	c := b.newCollector(ctx)
+
+	var (
+		offer        = Offer{URL: url}
+		metaTitle    string
+		availability string
+		outOfStock   bool
+		data         productData
+	)
+
+	onFirst(c, append(page.prices, metaPriceSelectors...), func(text string) bool {
+		price, err := parsePrice(text)
+		if err != nil || price <= 0 {
+			return false
+		}
+		offer.Price = price
+		return true
+	})
+	onFirst(c, page.titles, func(text string) bool {
+		offer.Title = text
+		return text != ""
+	})
+	onFirst(c, metaTitleSelectors, func(text string) bool {
+		metaTitle = text
+		return text != ""
+	})
+	onFirst(c, page.sellers, func(text string) bool {
+		offer.Seller = text
+		return text != ""
+	})
+	onFirst(c, metaAvailabilitySelectors, func(text string) bool {
+		availability = text
+		return text != ""
+	})
+
+	for _, sel := range page.outOfStock {
+		c.OnHTML(sel, func(e *colly.HTMLElement) {
+			outOfStock = true
+		})
+	}
+
+	c.OnHTML("script[type='application/ld+json']", func(e *colly.HTMLElement) {
+		if found, ok := parseProductData(e.Text); ok && data.name == "" && data.price == 0 {
+			data = found
+		}
+	})
+
+	if err := visit(ctx, c, url); err != nil {
+		return nil, err
+	}
+
+	// the JSON-LD product only fills what the page selectors missed
+	if offer.Price == 0 {
+		offer.Price = data.price
+	}
+	if offer.Title == "" {
+		offer.Title = data.name
+	}
+	if offer.Title == "" {
+		offer.Title = metaTitle
+	}
+	if offer.Seller == "" {
+		offer.Seller = data.seller
+	}
+	if availability == "" {
+		availability = data.availability
+	}
+
+	offer.Availability = parseAvailability(availability)
+	if outOfStock {
+		offer.Availability = AvailabilityOutOfStock
+	}
+	offer.Condition = parseCondition(offer.Title)
+
+	if offer.Price == 0 {
+		if offer.Availability == AvailabilityOutOfStock {
+			return nil, errors.New("price not found, the product is out of stock")
+		}
+		return nil, errors.New("price not found")
+	}
+
+	result := newScrapeResult(b.name)
+	result.add(offer)
+	return result, result.choose(itm, false)
+}
+
+// onFirst calls set with the value of the selectors in order until it
+// returns true, later matches are ignored
+func onFirst(c *colly.Collector, selectors []selector, set func(text string) bool) {
+	done := false
+	for _, sel := range selectors {
+		c.OnHTML(sel.selector, func(e *colly.HTMLElement) {
+			if done {
+				return
+			}
+
+			text := e.Text
+			if sel.attr != "" {
+				text = e.Attr(sel.attr)
+			}
+			done = set(strings.TrimSpace(text))
+		})
+	}
+}
+
+// productData is the schema.org Product published as JSON-LD
+type productData struct {
+	name         string
+	price        float64
+	availability string
+	seller       string
+}
+
+// parseProductData returns the first Product in a JSON-LD script, which may
+// hold a single object, a list or a @graph
+func parseProductData(text string) (productData, bool) {
+	var doc any
+	if err := json.Unmarshal([]byte(text), &doc); err != nil {
+		return productData{}, false
+	}
+
+	product := findProduct(doc)
+	if product == nil {
+		return productData{}, false
+	}
+
+	data := productData{name: jsonString(product["name"])}
+
+	offer, _ := product["offers"].(map[string]any)
+	if offers, ok := product["offers"].([]any); ok && len(offers) > 0 {
+		offer, _ = offers[0].(map[string]any)
+	}
+	if offer == nil {
+		return data, true
+	}
+
+	for _, key := range []string{"price", "lowPrice"} {
+		if price := jsonPrice(offer[key]); price > 0 {
+			data.price = price
+			break
+		}
+	}
+	data.availability = jsonString(offer["availability"])
+	if seller, ok := offer["seller"].(map[string]any); ok {
+		data.seller = jsonString(seller["name"])
+	}
+	return data, true
+}
+
+// findProduct returns the first object whose @type is Product
+func findProduct(value any) map[string]any {
+	switch v := value.(type) {
+	case []any:
+		for _, element := range v {
+			if product := findProduct(element); product != nil {
+				return product
+			}
+		}
+	case map[string]any:
+		if isProductType(v["@type"]) {
+			return v
+		}
+		return findProduct(v["@graph"])
+	}
+	return nil
+}
+
+func isProductType(value any) bool {
+	switch v := value.(type) {
+	case string:
+		return v == "Product"
+	case []any:
+		for _, t := range v {
+			if t == "Product" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func jsonString(value any) string {
+	s, _ := value.(string)
+	return strings.TrimSpace(s)
+}
+
+// jsonPrice reads prices published as numbers or strings
+func jsonPrice(value any) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case string:
+		price, err := parsePrice(v)
+		if err != nil {
+			return 0
+		}
+		return price
+	}
+	return 0
+}
@@ -0,0 +1,106 @@
+package sources
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Source)
+)
+
+// Register makes a source available by its name and aliases. It panics if
+// a name is already taken, sources are expected to register in init.
+func Register(s Source) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, name := range append([]string{s.Name()}, s.Aliases()...) {
+		key := normalizeName(name)
+		if _, ok := registry[key]; ok {
+			panic(fmt.Sprintf("sources: source %q registered twice", name))
+		}
+		registry[key] = s
+	}
+}
+
+// Lookup returns the source registered with the given name or alias
+func Lookup(name string) (Source, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	s, ok := registry[normalizeName(name)]
+	return s, ok
+}
+
+// All returns every registered source sorted by name
+func All() []Source {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	seen := make(map[Source]bool)
+	var all []Source
+	for _, s := range registry {
+		if !seen[s] {
+			seen[s] = true
+			all = append(all, s)
+		}
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Name() < all[j].Name()
+	})
+	return all
+}
+
+// Names returns the names of every registered source sorted
+func Names() []string {
+	all := All()
+	names := make([]string, len(all))
+	for i, s := range all {
+		names[i] = s.Name()
+	}
+	return names
+}
+
+// CanonicalURLs returns the product pages keyed by the registered name of
+// their source, unknown sources keep their trimmed name and empty pages are
+// dropped. When several keys name the same source the registered name wins,
+// then the first key in order.
+func CanonicalURLs(urls map[string]string) map[string]string {
+	keys := make([]string, 0, len(urls))
+	for key := range urls {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var canonical map[string]string
+	exact := make(map[string]bool)
+	for _, key := range keys {
+		page := strings.TrimSpace(urls[key])
+		name := strings.TrimSpace(key)
+		if page == "" || name == "" {
+			continue
+		}
+		if s, ok := Lookup(name); ok {
+			name = s.Name()
+		}
+
+		if _, ok := canonical[name]; ok && (exact[name] || key != name) {
+			continue
+		}
+		if canonical == nil {
+			canonical = make(map[string]string)
+		}
+		canonical[name] = page
+		exact[name] = key == name
+	}
+	return canonical
+}
+
+func normalizeName(name string) string {
+	return strings.TrimSpace(strings.ToLower(name))
+}
@@ -0,0 +1,44 @@
+package sources
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCanonicalURLs(t *testing.T) {
+	tests := []struct {
+		name string
+		urls map[string]string
+		want map[string]string
+	}{
+		{"nil", nil, nil},
+		{
+			name: "aliases and case",
+			urls: map[string]string{"amazon": " https://amazon.com.br/dp/B0 ", "MERCADO LIVRE": "https://mercadolivre.com.br/p/1"},
+			want: map[string]string{"Amazon": "https://amazon.com.br/dp/B0", "Mercado Livre": "https://mercadolivre.com.br/p/1"},
+		},
+		{
+			name: "registered name wins",
+			urls: map[string]string{"amazon": "https://amazon.com.br/dp/lower", "Amazon": "https://amazon.com.br/dp/exact", "AMAZON": "https://amazon.com.br/dp/upper"},
+			want: map[string]string{"Amazon": "https://amazon.com.br/dp/exact"},
+		},
+		{
+			name: "first key wins without the registered name",
+			urls: map[string]string{"amazon": "https://amazon.com.br/dp/lower", "AMAZON": "https://amazon.com.br/dp/upper"},
+			want: map[string]string{"Amazon": "https://amazon.com.br/dp/upper"},
+		},
+		{
+			name: "unknown sources and empty pages",
+			urls: map[string]string{" Kabum ": "https://kabum.com.br/p/1", "Amazon": " ", "": "https://example.com"},
+			want: map[string]string{"Kabum": "https://kabum.com.br/p/1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanonicalURLs(tt.urls); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CanonicalURLs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
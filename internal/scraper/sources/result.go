@@ -0,0 +1,209 @@
+package sources
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+	"github.com/WellyngtonF/WishListCLI/internal/scraper/matcher"
+)
+
+// Currency used by the brazilian stores
+const currencyBRL = "BRL"
+
+// Condition of the product sold in an offer
+type Condition string
+
+const (
+	ConditionUnknown Condition = ""
+	ConditionNew     Condition = "new"
+	ConditionUsed    Condition = "used"
+)
+
+// Availability of an offer
+type Availability string
+
+const (
+	AvailabilityUnknown    Availability = ""
+	AvailabilityInStock    Availability = "in_stock"
+	AvailabilityOutOfStock Availability = "out_of_stock"
+)
+
+// Reasons an offer is not eligible to be the best one
+const (
+	RejectedBelowMinPrice = "below minimum price"
+	RejectedOutOfStock    = "out of stock"
+	RejectedLowRelevance  = "low relevance"
+)
+
+// Offer is a product listing found on a source
+type Offer struct {
+	Title        string
+	Price        float64
+	Currency     string
+	Seller       string
+	ShippingCost float64
+	// FreeShipping is set when the listing advertises free shipping, when it
+	// is not set and ShippingCost is zero the cost is unknown
+	FreeShipping bool
+	Availability Availability
+	Condition    Condition
+	Rating       float64
+	URL          string
+	// Score is the relevance of the title for the item from 0 to 1, offers
+	// scraped from a product page or without a title are not scored and keep zero
+	Score float64
+	// Rejected explains why the offer was not eligible, empty when it was
+	Rejected string
+}
+
+// TotalPrice returns the price including shipping
+func (o Offer) TotalPrice() float64 {
+	return o.Price + o.ShippingCost
+}
+
+// ScrapeResult is everything a source found for an item
+type ScrapeResult struct {
+	Source string
+	// Offers holds every offer found in page order, including rejected ones
+	Offers []Offer
+	// BestIndex is the index in Offers of the cheapest eligible offer, -1 if none
+	BestIndex int
+}
+
+// newScrapeResult creates an empty result of the source
+func newScrapeResult(source string) *ScrapeResult {
+	return &ScrapeResult{Source: source, BestIndex: -1}
+}
+
+// Best returns the chosen offer or nil when no offer was eligible
+func (r *ScrapeResult) Best() *Offer {
+	if r == nil || r.BestIndex < 0 || r.BestIndex >= len(r.Offers) {
+		return nil
+	}
+	return &r.Offers[r.BestIndex]
+}
+
+// add appends an offer, defaulting its currency
+func (r *ScrapeResult) add(offer Offer) {
+	if offer.Currency == "" {
+		offer.Currency = currencyBRL
+	}
+	offer.Title = strings.TrimSpace(offer.Title)
+	offer.Seller = strings.TrimSpace(offer.Seller)
+	r.Offers = append(r.Offers, offer)
+}
+
+// choose rejects the offers below the item MinPrice, out of stock or, when
+// score is set, not relevant for the item, and picks the eligible offer with
+// the lowest total price. Offers without a title cannot be scored and are kept.
+func (r *ScrapeResult) choose(itm item.Item, score bool) error {
+	r.BestIndex = -1
+	m := matcher.New()
+
+	for i := range r.Offers {
+		offer := &r.Offers[i]
+
+		if score && offer.Title != "" {
+			match := m.Score(itm, offer.Title)
+			offer.Score = match.Score
+			if !m.Accept(match) && offer.Rejected == "" {
+				offer.Rejected = RejectedLowRelevance
+				if match.Reason != "" {
+					offer.Rejected = fmt.Sprintf("%s: %s", RejectedLowRelevance, match.Reason)
+				}
+			}
+		}
+
+		switch {
+		case offer.Rejected != "":
+			continue
+		case offer.Price < itm.MinPrice:
+			offer.Rejected = RejectedBelowMinPrice
+			continue
+		case offer.Availability == AvailabilityOutOfStock:
+			offer.Rejected = RejectedOutOfStock
+			continue
+		}
+
+		if best := r.Best(); best == nil || offer.TotalPrice() < best.TotalPrice() {
+			r.BestIndex = i
+		}
+	}
+
+	if len(r.Offers) == 0 {
+		return errors.New("no products found")
+	}
+	if r.BestIndex < 0 {
+		return errors.New("no eligible offers found")
+	}
+	return nil
+}
+
+// conditionWords are the whole words marking the condition of a listing,
+// used words are checked first as titles like "Usado, como novo" name both
+var (
+	usedWords = map[string]bool{
+		"seminovo": true, "seminova": true, "usado": true, "usada": true,
+		"recondicionado": true, "recondicionada": true,
+		"used": true, "refurbished": true, "renewed": true,
+	}
+	newWords = map[string]bool{"novo": true, "nova": true, "new": true}
+)
+
+// parseCondition detects new, used or refurbished products from the words of a listing text
+func parseCondition(text string) Condition {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	condition := ConditionUnknown
+	for _, word := range words {
+		switch {
+		case usedWords[word]:
+			return ConditionUsed
+		case newWords[word]:
+			condition = ConditionNew
+		}
+	}
+	return condition
+}
+
+// shippingPrice finds the amount in shipping texts like "Frete R$ 19,90"
+var shippingPrice = regexp.MustCompile(`R\$\s*([0-9.]+(?:,[0-9]{1,2})?)`)
+
+// parseShipping reads a listing shipping text, returning whether it is free
+// and its cost when one is shown
+func parseShipping(text string) (cost float64, free bool) {
+	lower := strings.ToLower(text)
+	if strings.Contains(lower, "grátis") || strings.Contains(lower, "gratis") {
+		return 0, true
+	}
+
+	if match := shippingPrice.FindStringSubmatch(text); match != nil {
+		if cost, err := parsePrice(match[1]); err == nil {
+			return cost, false
+		}
+	}
+	return 0, false
+}
+
+// parseAvailability reads schema.org availability values such as
+// "https://schema.org/InStock" or plain texts like "Em estoque"
+func parseAvailability(text string) Availability {
+	text = strings.ToLower(text)
+	switch {
+	case strings.Contains(text, "outofstock"), strings.Contains(text, "out of stock"),
+		strings.Contains(text, "soldout"), strings.Contains(text, "indisponível"),
+		strings.Contains(text, "esgotado"):
+		return AvailabilityOutOfStock
+	case strings.Contains(text, "instock"), strings.Contains(text, "in stock"), strings.Contains(text, "em estoque"),
+		strings.Contains(text, "disponível"):
+		return AvailabilityInStock
+	default:
+		return AvailabilityUnknown
+	}
+}
@@ -0,0 +1,82 @@
+package sources
+
+import "testing"
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		text string
+		want float64
+	}{
+		{"R$ 1.234,56", 1234.56},
+		{"1.234,5", 1234.5},
+		{"3.799", 3799},
+		{"1.234.567", 1234567},
+		{"1234.56", 1234.56},
+		{"  R$ 99,90 ", 99.9},
+		{"499.90", 499.9},
+		{"12", 12},
+	}
+
+	for _, tt := range tests {
+		got, err := parsePrice(tt.text)
+		if err != nil {
+			t.Errorf("parsePrice(%q) error: %v", tt.text, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parsePrice(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+
+	for _, text := range []string{"", "R$", "grátis"} {
+		if _, err := parsePrice(text); err == nil {
+			t.Errorf("parsePrice(%q) expected an error", text)
+		}
+	}
+}
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		text string
+		want Condition
+	}{
+		{"PlayStation 5 Novo Lacrado", ConditionNew},
+		{"Console PS5 seminovo", ConditionUsed},
+		{"iPhone 13 Seminova", ConditionUsed},
+		{"Notebook usado, como novo", ConditionUsed},
+		{"Kindle (Renewed)", ConditionUsed},
+		{"Unused controller", ConditionUnknown},
+		{"Newport headphones", ConditionUnknown},
+		{"Brand new PS5", ConditionNew},
+		{"Cadeira gamer", ConditionUnknown},
+		{"", ConditionUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := parseCondition(tt.text); got != tt.want {
+			t.Errorf("parseCondition(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseShipping(t *testing.T) {
+	tests := []struct {
+		text     string
+		wantCost float64
+		wantFree bool
+	}{
+		{"Frete grátis", 0, true},
+		{"Frete gratis para todo o país", 0, true},
+		{"Frete R$ 19,90", 19.9, false},
+		{"Frete: R$1.020", 1020, false},
+		{"Chegará amanhã", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		cost, free := parseShipping(tt.text)
+		if cost != tt.wantCost || free != tt.wantFree {
+			t.Errorf("parseShipping(%q) = %v, %v, want %v, %v", tt.text, cost, free, tt.wantCost, tt.wantFree)
+		}
+	}
+}
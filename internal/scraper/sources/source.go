@@ -0,0 +1,23 @@
+package sources
+
+import (
+	"context"
+
+	"github.com/WellyngtonF/WishListCLI/internal/item"
+)
+
+// Source is an online store the item prices are scraped from
+type Source interface {
+	// Name returns the name shown to the user and stored in item.ScrapingSources
+	Name() string
+	// Aliases returns other names accepted for the source
+	Aliases() []string
+	// Domain returns the domain the source sends its requests to
+	Domain() string
+	// Search searches the item by name and returns the offers found
+	Search(ctx context.Context, itm item.Item) (*ScrapeResult, error)
+	// ScrapeURL scrapes the offer of the product page at url
+	ScrapeURL(ctx context.Context, itm item.Item, url string) (*ScrapeResult, error)
+	// HandlesURL reports whether url is a page ScrapeURL can scrape
+	HandlesURL(url string) bool
+}
@@ -1,17 +1,25 @@
 package utils
 
 import (
-	"fmt"
+	"errors"
+	"time"
 
 	"github.com/spf13/viper"
 	"golang.org/x/exp/rand"
 )
 
+func init() {
+	rand.Seed(uint64(time.Now().UnixNano()))
+}
+
+// ErrNoProxy is returned when the config has no proxy URLs
+var ErrNoProxy = errors.New("no proxy URLs found in config")
+
 func GetRandomProxy() (string, string, string, error) {
 
 	proxyURLs := viper.GetStringSlice("proxy_urls")
 	if len(proxyURLs) == 0 {
-		return "", "", "", fmt.Errorf("no proxy URLs found in config")
+		return "", "", "", ErrNoProxy
 	}
 
 	return proxyURLs[rand.Intn(len(proxyURLs))], viper.GetString("proxy_username"), viper.GetString("proxy_password"), nil